@@ -0,0 +1,192 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdint.h>
+// #include <stdlib.h>
+// extern void gosqlite3_step(sqlite3_context*, int, sqlite3_value**);
+// extern void gosqlite3_final(sqlite3_context*);
+// extern void gosqlite3_value(sqlite3_context*);
+// extern void gosqlite3_inverse(sqlite3_context*, int, sqlite3_value**);
+// extern void gosqlite3_release(void*);
+// static int gosqlite3_create_aggregate(sqlite3* db, const char* name, int nargs, int flags, uintptr_t h, int window) {
+//     if (window) {
+//         return sqlite3_create_window_function(db, name, nargs, flags, (void*)h, gosqlite3_step, gosqlite3_final, gosqlite3_value, gosqlite3_inverse, gosqlite3_release);
+//     }
+//     return sqlite3_create_window_function(db, name, nargs, flags, (void*)h, gosqlite3_step, gosqlite3_final, NULL, NULL, gosqlite3_release);
+// }
+// static int gosqlite3_delete_aggregate(sqlite3* db, const char* name, int nargs) {
+//     return sqlite3_create_window_function(db, name, nargs, SQLITE_UTF8, NULL, NULL, NULL, NULL, NULL, NULL);
+// }
+// static uintptr_t* gosqlite3_aggregate_state(sqlite3_context* ctx) {
+//     return (uintptr_t*)sqlite3_aggregate_context(ctx, sizeof(uintptr_t));
+// }
+import "C"
+import (
+	"fmt"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// Aggregator accumulates the rows of a single group for a user-defined
+// aggregate function.
+//
+// Step is called once for each row in the group with the SQL arguments
+// converted as by Value.Interface, and Final returns the result of the
+// aggregate which is converted as by QueryParameter.Bind.
+type Aggregator interface {
+	Step(args ...interface{}) error
+	Final() (interface{}, error)
+}
+
+// WindowAggregator is an Aggregator which can also be used as an aggregate
+// window function with sliding frames.
+//
+// Inverse removes the oldest row, previously passed to Step, from the
+// current window and Value returns the result for the current window
+// without ending the group.
+type WindowAggregator interface {
+	Aggregator
+	Inverse(args ...interface{}) error
+	Value() (interface{}, error)
+}
+
+// aggregate holds the factory of a registered aggregate. The Aggregator for
+// each group lives on the Go side and SQLite's aggregate context only holds
+// its handle. `first` is the Aggregator created during registration, which
+// is used for the first group rather than discarded.
+type aggregate struct {
+	factory func() Aggregator
+	first	Aggregator
+}
+
+// state returns the Aggregator for the group being evaluated in ctx,
+// creating it on first use.
+func (a *aggregate) state(ctx *C.sqlite3_context) (h *C.uintptr_t, g Aggregator) {
+	if h = C.gosqlite3_aggregate_state(ctx); h == nil {
+		return
+	}
+	if *h == 0 {
+		if g, a.first = a.first, nil; g == nil {
+			g = a.factory()
+		}
+		*h = newHandle(g)
+	}
+	g = cgo.Handle(*h).Value().(Aggregator)
+	return
+}
+
+// step passes the SQL arguments to Step, or to Inverse when `inverse` is
+// set.
+func (a *aggregate) step(ctx *C.sqlite3_context, argv []*C.sqlite3_value, inverse bool) {
+	defer func() {
+		if x := recover(); x != nil {
+			setResult(ctx, fmt.Errorf("sqlite3: panic in aggregate: %v", x))
+		}
+	}()
+	_, g := a.state(ctx)
+	if g == nil {
+		C.sqlite3_result_error_nomem(ctx)
+		return
+	}
+	args := make([]interface{}, len(argv))
+	for i, v := range argv {
+		args[i] = (Value{v}).Interface()
+	}
+	var e error
+	if inverse {
+		e = g.(WindowAggregator).Inverse(args...)
+	} else {
+		e = g.Step(args...)
+	}
+	if e != nil {
+		setResult(ctx, e)
+	}
+}
+
+// value reports the result for the current window.
+func (a *aggregate) value(ctx *C.sqlite3_context) {
+	defer func() {
+		if x := recover(); x != nil {
+			setResult(ctx, fmt.Errorf("sqlite3: panic in aggregate: %v", x))
+		}
+	}()
+	_, g := a.state(ctx)
+	if g == nil {
+		C.sqlite3_result_error_nomem(ctx)
+		return
+	}
+	if v, e := g.(WindowAggregator).Value(); e != nil {
+		setResult(ctx, e)
+	} else {
+		setResult(ctx, v)
+	}
+}
+
+func (a *aggregate) final(ctx *C.sqlite3_context) {
+	defer func() {
+		if x := recover(); x != nil {
+			setResult(ctx, fmt.Errorf("sqlite3: panic in aggregate: %v", x))
+		}
+	}()
+	h, g := a.state(ctx)
+	if g == nil {
+		C.sqlite3_result_error_nomem(ctx)
+		return
+	}
+	defer func() {
+		cgo.Handle(*h).Delete()
+		*h = 0
+	}()
+	if v, e := g.Final(); e != nil {
+		setResult(ctx, e)
+	} else {
+		setResult(ctx, v)
+	}
+}
+
+//export gosqlite3_step
+func gosqlite3_step(ctx *C.sqlite3_context, argc C.int, argv **C.sqlite3_value) {
+	a := handleValue(C.sqlite3_user_data(ctx)).(*aggregate)
+	a.step(ctx, unsafe.Slice(argv, int(argc)), false)
+}
+
+//export gosqlite3_inverse
+func gosqlite3_inverse(ctx *C.sqlite3_context, argc C.int, argv **C.sqlite3_value) {
+	a := handleValue(C.sqlite3_user_data(ctx)).(*aggregate)
+	a.step(ctx, unsafe.Slice(argv, int(argc)), true)
+}
+
+//export gosqlite3_value
+func gosqlite3_value(ctx *C.sqlite3_context) {
+	a := handleValue(C.sqlite3_user_data(ctx)).(*aggregate)
+	a.value(ctx)
+}
+
+//export gosqlite3_final
+func gosqlite3_final(ctx *C.sqlite3_context) {
+	a := handleValue(C.sqlite3_user_data(ctx)).(*aggregate)
+	a.final(ctx)
+}
+
+// CreateAggregate registers the SQL aggregate function `name` taking
+// `nargs` arguments, or any number when `nargs` is -1. `factory` is called
+// to create a fresh Aggregator for each group.
+//
+// When the Aggregators returned by `factory` implement WindowAggregator the
+// function may also be used as a window function with an OVER clause. The
+// first Aggregator is created during registration to find out, and is used
+// for the first group. Passing a nil `factory` removes a previously
+// registered aggregate.
+func (db *Database) CreateAggregate(name string, nargs int, factory func() Aggregator) (e error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if factory == nil {
+		return SQLiteError(C.gosqlite3_delete_aggregate(db.handle, cname, C.int(nargs)))
+	}
+	a := &aggregate{factory: factory, first: factory()}
+	var window C.int
+	if _, ok := a.first.(WindowAggregator); ok {
+		window = 1
+	}
+	return SQLiteError(C.gosqlite3_create_aggregate(db.handle, cname, C.int(nargs), C.SQLITE_UTF8, newHandle(a), window))
+}
@@ -0,0 +1,120 @@
+package sqlite3
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+type median []float64
+
+func (m *median) Step(args ...interface{}) error {
+	switch v := args[0].(type) {
+	case int64:
+		*m = append(*m, float64(v))
+	case float64:
+		*m = append(*m, v)
+	case nil:
+	default:
+		return errors.New("median of non-numeric value")
+	}
+	return nil
+}
+
+func (m *median) Final() (interface{}, error) {
+	if len(*m) == 0 {
+		return nil, nil
+	}
+	sort.Float64s(*m)
+	if n := len(*m); n % 2 == 0 {
+		return ((*m)[n / 2 - 1] + (*m)[n / 2]) / 2, nil
+	}
+	return (*m)[len(*m) / 2], nil
+}
+
+func TestCreateAggregate(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		FOO.Create(db)
+		db.createTestData(t, 0)
+		for i, v := range []int{7, 1, 3, 10, 2} {
+			db.runQuery(t, "INSERT INTO foo VALUES (?, ?)", v, []string{"odd", "even"}[i % 2])
+		}
+		created := 0
+		fatalOnError(t, db.CreateAggregate("median", 1, func() Aggregator {
+			created++
+			return new(median)
+		}), "unable to register median")
+
+		if v := db.queryValue(t, "SELECT median(number) FROM foo"); v != float64(3) || created != 1 {
+			t.Fatalf("median returned %v after creating %v aggregators", v, created)
+		}
+		if v := db.queryValue(t, "SELECT median(number) FROM foo WHERE number > 100"); v != nil {
+			t.Fatalf("median of empty set returned %v", v)
+		}
+		groups := map[string]interface{}{}
+		_, e := db.Execute("SELECT text, median(number) FROM foo GROUP BY text", func(s *Statement, values ...interface{}) {
+			groups[values[0].(string)] = values[1]
+		})
+		fatalOnError(t, e, "unable to run grouped median")
+		if groups["odd"] != float64(3) || groups["even"] != 5.5 {
+			t.Fatalf("unexpected grouped medians %v", groups)
+		}
+		if _, e = db.Execute("SELECT median(text) FROM foo"); e == nil {
+			t.Fatal("median of text should have failed")
+		}
+
+		fatalOnError(t, db.CreateAggregate("median", 1, nil), "unable to remove median")
+		if _, e = db.Execute("SELECT median(number) FROM foo"); e == nil {
+			t.Fatal("removed aggregate still callable")
+		}
+	})
+}
+
+type windowSum int64
+
+func (w *windowSum) Step(args ...interface{}) error {
+	*w += windowSum(args[0].(int64))
+	return nil
+}
+
+func (w *windowSum) Inverse(args ...interface{}) error {
+	*w -= windowSum(args[0].(int64))
+	return nil
+}
+
+func (w *windowSum) Value() (interface{}, error) {
+	return int64(*w), nil
+}
+
+func (w *windowSum) Final() (interface{}, error) {
+	return int64(*w), nil
+}
+
+func TestWindowAggregate(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		FOO.Create(db)
+		for _, v := range []int{1, 2, 3, 4, 5} {
+			db.runQuery(t, "INSERT INTO foo VALUES (?, 'window')", v)
+		}
+		fatalOnError(t, db.CreateAggregate("wsum", 1, func() Aggregator { return new(windowSum) }), "unable to register wsum")
+
+		sums := []int64{}
+		_, e := db.Execute("SELECT wsum(number) OVER (ORDER BY number ROWS BETWEEN 1 PRECEDING AND CURRENT ROW) FROM foo", func(s *Statement, values ...interface{}) {
+			sums = append(sums, values[0].(int64))
+		})
+		fatalOnError(t, e, "unable to run window function")
+		for i, v := range []int64{1, 3, 5, 7, 9} {
+			if sums[i] != v {
+				t.Fatalf("unexpected sliding sums %v", sums)
+			}
+		}
+		if v := db.queryValue(t, "SELECT wsum(number) FROM foo"); v != int64(15) {
+			t.Fatalf("wsum as aggregate returned %v", v)
+		}
+
+		fatalOnError(t, db.CreateAggregate("median", 1, func() Aggregator { return new(median) }), "unable to register median")
+		if _, e = db.Execute("SELECT median(number) OVER (ROWS 1 PRECEDING) FROM foo"); e == nil {
+			t.Fatal("median used as sliding window function")
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdint.h>
+// extern int gosqlite3_authorizer_hook(uintptr_t, int, char*, char*, char*, char*);
+// static int gosqlite3_authorize(void* p, int action, const char* arg1, const char* arg2, const char* db, const char* trigger) {
+//     return gosqlite3_authorizer_hook((uintptr_t)p, action, (char*)arg1, (char*)arg2, (char*)db, (char*)trigger);
+// }
+// static int gosqlite3_set_authorizer(sqlite3* db, uintptr_t h) {
+//     return sqlite3_set_authorizer(db, h ? gosqlite3_authorize : NULL, (void*)h);
+// }
+import "C"
+import (
+	"runtime/cgo"
+	"strings"
+)
+
+// AuthAction identifies the operation an authorizer is asked to approve.
+// The comment on each action describes the `arg1` and `arg2` passed with
+// it.
+type AuthAction int
+
+const (
+	ACTION_CREATE_INDEX AuthAction = iota + 1	// Index Name		Table Name
+	ACTION_CREATE_TABLE							// Table Name		-
+	ACTION_CREATE_TEMP_INDEX					// Index Name		Table Name
+	ACTION_CREATE_TEMP_TABLE					// Table Name		-
+	ACTION_CREATE_TEMP_TRIGGER					// Trigger Name		Table Name
+	ACTION_CREATE_TEMP_VIEW						// View Name		-
+	ACTION_CREATE_TRIGGER						// Trigger Name		Table Name
+	ACTION_CREATE_VIEW							// View Name		-
+	ACTION_DELETE								// Table Name		-
+	ACTION_DROP_INDEX							// Index Name		Table Name
+	ACTION_DROP_TABLE							// Table Name		-
+	ACTION_DROP_TEMP_INDEX						// Index Name		Table Name
+	ACTION_DROP_TEMP_TABLE						// Table Name		-
+	ACTION_DROP_TEMP_TRIGGER					// Trigger Name		Table Name
+	ACTION_DROP_TEMP_VIEW						// View Name		-
+	ACTION_DROP_TRIGGER							// Trigger Name		Table Name
+	ACTION_DROP_VIEW							// View Name		-
+	ACTION_INSERT								// Table Name		-
+	ACTION_PRAGMA								// Pragma Name		1st arg or -
+	ACTION_READ									// Table Name		Column Name
+	ACTION_SELECT								// -				-
+	ACTION_TRANSACTION							// Operation		-
+	ACTION_UPDATE								// Table Name		Column Name
+	ACTION_ATTACH								// Filename			-
+	ACTION_DETACH								// Database Name	-
+	ACTION_ALTER_TABLE							// Database Name	Table Name
+	ACTION_REINDEX								// Index Name		-
+	ACTION_ANALYZE								// Table Name		-
+	ACTION_CREATE_VTABLE						// Table Name		Module Name
+	ACTION_DROP_VTABLE							// Table Name		Module Name
+	ACTION_FUNCTION								// -				Function Name
+	ACTION_SAVEPOINT							// Operation		Savepoint Name
+	ACTION_RECURSIVE							// -				-
+)
+
+// AuthResult is the verdict of an authorizer.
+//
+// AUTH_DENY makes the statement fail with an AUTH error, while AUTH_IGNORE
+// lets it run but treats the column being read as NULL, or silently skips
+// the operation.
+type AuthResult int
+
+const (
+	AUTH_OK AuthResult = iota
+	AUTH_DENY
+	AUTH_IGNORE
+)
+
+// Authorizer is called while statements are prepared to approve each
+// operation they will perform. `dbName` is the database holding the object
+// and `trigger` the innermost trigger or view responsible for the access,
+// either of which may be empty.
+type Authorizer func(action AuthAction, arg1, arg2, dbName, trigger string) AuthResult
+
+// SetAuthorizer installs `f` as the authorizer of the database, replacing
+// any previous one. Passing nil removes the authorizer. An operation for
+// which `f` panics is denied.
+//
+// Statements are authorized when they are prepared, so statements prepared
+// before the call are not affected.
+func (db *Database) SetAuthorizer(f func(action AuthAction, arg1, arg2, dbName, trigger string) AuthResult) (e error) {
+	var v interface{}
+	if f != nil {
+		v = Authorizer(f)
+	}
+	db.setHook("authorizer", v, func(h C.uintptr_t) {
+		e = SQLiteError(C.gosqlite3_set_authorizer(db.handle, h))
+	})
+	return
+}
+
+//export gosqlite3_authorizer_hook
+func gosqlite3_authorizer_hook(h C.uintptr_t, action C.int, arg1, arg2, db, trigger *C.char) (r C.int) {
+	defer func() {
+		if recover() != nil {
+			r = C.int(AUTH_DENY)
+		}
+	}()
+	f := cgo.Handle(h).Value().(Authorizer)
+	return C.int(f(AuthAction(action), C.GoString(arg1), C.GoString(arg2), C.GoString(db), C.GoString(trigger)))
+}
+
+// ReadOnlyAuthorizer permits queries and denies everything else, including
+// writes, schema changes, ATTACH, DETACH and PRAGMA.
+func ReadOnlyAuthorizer(action AuthAction, arg1, arg2, dbName, trigger string) AuthResult {
+	switch action {
+	case ACTION_SELECT, ACTION_READ, ACTION_FUNCTION, ACTION_RECURSIVE:
+		return AUTH_OK
+	}
+	return AUTH_DENY
+}
+
+// TableAllowList returns an Authorizer which denies reading or writing any
+// table not named in `tables`. Names are compared without regard to case.
+func TableAllowList(tables ...string) Authorizer {
+	allowed := make(map[string]bool)
+	for _, t := range tables {
+		allowed[strings.ToLower(t)] = true
+	}
+	return func(action AuthAction, arg1, arg2, dbName, trigger string) AuthResult {
+		switch action {
+		case ACTION_READ, ACTION_INSERT, ACTION_UPDATE, ACTION_DELETE:
+			if !allowed[strings.ToLower(arg1)] {
+				return AUTH_DENY
+			}
+		}
+		return AUTH_OK
+	}
+}
+
+// Authorizers combines several authorizers into one which returns the
+// most restrictive of their results.
+func Authorizers(a ...Authorizer) Authorizer {
+	return func(action AuthAction, arg1, arg2, dbName, trigger string) (r AuthResult) {
+		for _, f := range a {
+			switch f(action, arg1, arg2, dbName, trigger) {
+			case AUTH_DENY:
+				return AUTH_DENY
+			case AUTH_IGNORE:
+				r = AUTH_IGNORE
+			}
+		}
+		return
+	}
+}
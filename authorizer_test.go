@@ -0,0 +1,61 @@
+package sqlite3
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAuthorizer(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		FOO.Create(db)
+		BAR.Create(db)
+		db.runQuery(t, "INSERT INTO foo VALUES (1, 'holy moly')")
+
+		fatalOnError(t, db.SetAuthorizer(ReadOnlyAuthorizer), "unable to set read-only authorizer")
+		if v := db.queryValue(t, "SELECT text FROM foo"); v != "holy moly" {
+			t.Fatalf("read returned %v", v)
+		}
+		for _, sql := range []string{
+			"INSERT INTO foo VALUES (2, 'guacomole')",
+			"DELETE FROM foo",
+			"DROP TABLE foo",
+			"PRAGMA user_version = 3",
+			"ATTACH ':memory:' AS other",
+		} {
+			if _, e := db.Execute(sql); !errors.Is(e, AUTH) {
+				t.Fatalf("%v: expected %v, got %v", sql, AUTH, e)
+			}
+		}
+
+		fatalOnError(t, db.SetAuthorizer(Authorizers(ReadOnlyAuthorizer, TableAllowList("FOO"))), "unable to set table authorizer")
+		if v := db.queryValue(t, "SELECT COUNT(*) FROM foo"); v != int64(1) {
+			t.Fatalf("count returned %v", v)
+		}
+		if _, e := db.Execute("SELECT * FROM bar"); !errors.Is(e, AUTH) {
+			t.Fatalf("reading bar: expected %v, got %v", AUTH, e)
+		}
+
+		actions := []AuthAction{}
+		fatalOnError(t, db.SetAuthorizer(func(action AuthAction, arg1, arg2, dbName, trigger string) AuthResult {
+			actions = append(actions, action)
+			if action == ACTION_READ && arg2 == "text" {
+				return AUTH_IGNORE
+			}
+			return AUTH_OK
+		}), "unable to set custom authorizer")
+		if v := db.queryValue(t, "SELECT text FROM foo"); v != nil {
+			t.Fatalf("ignored column returned %v", v)
+		}
+		if len(actions) == 0 || actions[0] != ACTION_SELECT {
+			t.Fatalf("unexpected actions %v", actions)
+		}
+
+		fatalOnError(t, db.SetAuthorizer(nil), "unable to remove authorizer")
+		db.runQuery(t, "INSERT INTO foo VALUES (2, 'guacomole')")
+
+		fatalOnError(t, db.SetAuthorizer(func(AuthAction, string, string, string, string) AuthResult { panic("authorizer") }), "unable to set panicking authorizer")
+		if _, e := db.Execute("SELECT * FROM foo"); !errors.Is(e, AUTH) {
+			t.Fatalf("panicking authorizer: expected %v, got %v", AUTH, e)
+		}
+	})
+}
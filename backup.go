@@ -35,7 +35,7 @@ func NewBackup(d *Database, ddb string, s *Database, sdb string) (b *Backup, e e
 // Step will copy up to `pages` between the source and destination database.
 // If `pages` is negative, all remaining source pages are copied.
 func (b *Backup) Step(pages int) error {
-	return SQLiteError(C.sqlite3_backup_step(b.cptr, C.int(pages)))
+	return b.db.lastError(C.sqlite3_backup_step(b.cptr, C.int(pages)), "")
 }
 
 // Remaining returns the number of pages still to be backed up.
@@ -51,7 +51,7 @@ func (b *Backup) PageCount() int {
 // Finish should be called when the backup is done, an error occured or when 
 // the application wants to abandon the backup operation.
 func (b *Backup) Finish() error {
-	return SQLiteError(C.sqlite3_backup_finish(b.cptr))
+	return b.db.lastError(C.sqlite3_backup_finish(b.cptr), "")
 }
 
 // Full creates a full backup of the database.
@@ -0,0 +1,143 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// Blob provides incremental I/O on a single BLOB value.
+//
+// A Blob is opened on a particular row and column and allows that value to
+// be read and written in pieces without loading the whole value into memory.
+// The size of the value can not be changed through a Blob; use a SQL UPDATE
+// with zeroblob(N) to allocate space before writing.
+type Blob struct {
+	cptr		*C.sqlite3_blob
+	db			*Database
+	offset		int64
+}
+
+// OpenBlob opens the BLOB stored in `column` of the row with `rowid` in
+// `table` of the attached database `dbname` ("main" when empty).
+func (db *Database) OpenBlob(dbname, table, column string, rowid int64, writable bool) (b *Blob, e error) {
+	if dbname == "" {
+		dbname = "main"
+	}
+	cdb := C.CString(dbname)
+	defer C.free(unsafe.Pointer(cdb))
+	ctable := C.CString(table)
+	defer C.free(unsafe.Pointer(ctable))
+	ccolumn := C.CString(column)
+	defer C.free(unsafe.Pointer(ccolumn))
+
+	var flags C.int
+	if writable {
+		flags = 1
+	}
+	b = &Blob{db: db}
+	if e = SQLiteError(C.sqlite3_blob_open(db.handle, cdb, ctable, ccolumn, C.sqlite3_int64(rowid), flags, &b.cptr)); e != nil {
+		b = nil
+	}
+	return
+}
+
+// Size returns the size of the BLOB in bytes.
+func (b *Blob) Size() int64 {
+	return int64(C.sqlite3_blob_bytes(b.cptr))
+}
+
+// ReadAt implements io.ReaderAt.
+func (b *Blob) ReadAt(p []byte, off int64) (n int, e error) {
+	size := b.Size()
+	switch {
+	case off < 0:
+		return 0, errors.New("sqlite3: negative BLOB offset")
+	case off >= size:
+		return 0, io.EOF
+	case int64(len(p)) > size - off:
+		p = p[:size - off]
+		e = io.EOF
+	}
+	if len(p) > 0 {
+		if rv := SQLiteError(C.sqlite3_blob_read(b.cptr, unsafe.Pointer(&p[0]), C.int(len(p)), C.int(off))); rv != nil {
+			return 0, rv
+		}
+	}
+	return len(p), e
+}
+
+// WriteAt implements io.WriterAt. Writes beyond the end of the BLOB fail
+// with io.ErrShortWrite as the BLOB can not be resized.
+func (b *Blob) WriteAt(p []byte, off int64) (n int, e error) {
+	size := b.Size()
+	switch {
+	case off < 0:
+		return 0, errors.New("sqlite3: negative BLOB offset")
+	case off > size:
+		return 0, io.ErrShortWrite
+	case int64(len(p)) > size - off:
+		p = p[:size - off]
+		e = io.ErrShortWrite
+	}
+	if len(p) > 0 {
+		if rv := SQLiteError(C.sqlite3_blob_write(b.cptr, unsafe.Pointer(&p[0]), C.int(len(p)), C.int(off))); rv != nil {
+			return 0, rv
+		}
+	}
+	return len(p), e
+}
+
+// Read implements io.Reader, reading from the current offset.
+func (b *Blob) Read(p []byte) (n int, e error) {
+	n, e = b.ReadAt(p, b.offset)
+	b.offset += int64(n)
+	if e == io.EOF && n > 0 {
+		e = nil
+	}
+	return
+}
+
+// Write implements io.Writer, writing at the current offset.
+func (b *Blob) Write(p []byte) (n int, e error) {
+	n, e = b.WriteAt(p, b.offset)
+	b.offset += int64(n)
+	return
+}
+
+// Seek implements io.Seeker.
+func (b *Blob) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.offset
+	case io.SeekEnd:
+		offset += b.Size()
+	default:
+		return 0, errors.New("sqlite3: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("sqlite3: negative BLOB offset")
+	}
+	b.offset = offset
+	return offset, nil
+}
+
+// Reopen moves the Blob to the same column of the row with `rowid` in the
+// same table, which is faster than closing and opening a new Blob.
+func (b *Blob) Reopen(rowid int64) (e error) {
+	if e = SQLiteError(C.sqlite3_blob_reopen(b.cptr, C.sqlite3_int64(rowid))); e == nil {
+		b.offset = 0
+	}
+	return
+}
+
+// Close releases the Blob handle.
+func (b *Blob) Close() (e error) {
+	e = SQLiteError(C.sqlite3_blob_close(b.cptr))
+	b.cptr = nil
+	return
+}
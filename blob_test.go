@@ -0,0 +1,45 @@
+package sqlite3
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestBlobIO(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		_, e := db.Execute("CREATE TABLE files (id INTEGER PRIMARY KEY, data BLOB)")
+		fatalOnError(t, e, "unable to create table")
+		db.runQuery(t, "INSERT INTO files VALUES (1, zeroblob(16))")
+		db.runQuery(t, "INSERT INTO files VALUES (2, zeroblob(4))")
+
+		b, e := db.OpenBlob("", "files", "data", 1, true)
+		fatalOnError(t, e, "unable to open blob for writing")
+		if b.Size() != 16 {
+			t.Fatalf("expected 16 bytes, got %v", b.Size())
+		}
+		n, e := b.Write([]byte("holy moly"))
+		fatalOnError(t, e, "unable to write to blob")
+		_, e = b.WriteAt([]byte("guacomole"), 12)
+		if n != 9 || e != io.ErrShortWrite {
+			t.Fatalf("expected short write, got %v", e)
+		}
+
+		b.Seek(0, io.SeekStart)
+		data, e := io.ReadAll(b)
+		fatalOnError(t, e, "unable to read blob")
+		if !bytes.Equal(data[:13], []byte("holy moly\x00\x00\x00g")) {
+			t.Fatalf("unexpected blob content %q", data)
+		}
+
+		fatalOnError(t, b.Reopen(2), "unable to reopen blob")
+		buffer := make([]byte, 8)
+		if n, e = b.ReadAt(buffer, 0); n != 4 || e != io.EOF {
+			t.Fatalf("expected 4 bytes and EOF, got %v and %v", n, e)
+		}
+		fatalOnError(t, b.Close(), "unable to close blob")
+
+		_, e = db.OpenBlob("main", "files", "data", 3, false)
+		fatalOnSuccess(t, e, "opened blob for missing row")
+	})
+}
@@ -0,0 +1,83 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdint.h>
+// extern int gosqlite3_busy_hook(uintptr_t, int);
+// static int gosqlite3_busy(void* p, int attempt) {
+//     return gosqlite3_busy_hook((uintptr_t)p, attempt);
+// }
+// static int gosqlite3_set_busy_handler(sqlite3* db, uintptr_t h) {
+//     return sqlite3_busy_handler(db, h ? gosqlite3_busy : NULL, (void*)h);
+// }
+import "C"
+import (
+	"math/rand"
+	"runtime/cgo"
+	"time"
+)
+
+// SetBusyTimeout makes the database sleep and retry for up to `d` when a
+// table is locked by another connection, instead of failing immediately
+// with BUSY. A zero or negative duration turns off any busy handler.
+func (db *Database) SetBusyTimeout(d time.Duration) (e error) {
+	db.setHook("busy", nil, func(h C.uintptr_t) {
+		e = SQLiteError(C.sqlite3_busy_timeout(db.handle, C.int(d / time.Millisecond)))
+	})
+	return
+}
+
+// SetBusyHandler registers `f` to be called when a table is locked by
+// another connection. `attempt` counts the calls made for the current
+// lock, starting at 0. If `f` returns true the operation is retried,
+// otherwise, or if `f` panics, it fails with BUSY. Passing nil removes the
+// handler.
+//
+// The handler applies to every statement run on the database, including
+// those run by Execute, Begin and Commit, and replaces any timeout set with
+// SetBusyTimeout.
+func (db *Database) SetBusyHandler(f func(attempt int) bool) (e error) {
+	var v interface{}
+	if f != nil {
+		v = f
+	}
+	db.setHook("busy", v, func(h C.uintptr_t) {
+		e = SQLiteError(C.gosqlite3_set_busy_handler(db.handle, h))
+	})
+	return
+}
+
+//export gosqlite3_busy_hook
+func gosqlite3_busy_hook(h C.uintptr_t, attempt C.int) (r C.int) {
+	defer func() {
+		if recover() != nil {
+			r = 0
+		}
+	}()
+	if cgo.Handle(h).Value().(func(int) bool)(int(attempt)) {
+		return 1
+	}
+	return 0
+}
+
+// Backoff returns a busy handler which retries up to `attempts` times,
+// sleeping for an exponentially growing interval starting at `base` and
+// capped at `max`. Each sleep is chosen at random between half and all of
+// the interval, so that competing connections do not retry in lockstep.
+func Backoff(base, max time.Duration, attempts int) func(attempt int) bool {
+	return func(attempt int) bool {
+		if attempt >= attempts {
+			return false
+		}
+		d := max
+		if attempt < 32 {
+			if b := base << uint(attempt); b > 0 && b < max {
+				d = b
+			}
+		}
+		if d > 1 {
+			d = d / 2 + time.Duration(rand.Int63n(int64(d / 2)))
+		}
+		time.Sleep(d)
+		return true
+	}
+}
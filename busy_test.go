@@ -0,0 +1,61 @@
+package sqlite3
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBusyHandler(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "busy.db")
+	Session(filename, func(writer *Database) {
+		FOO.Create(writer)
+		db, e := Open(filename)
+		fatalOnError(t, e, "unable to open second connection")
+		defer db.Close()
+		lock := func() {
+			_, e := writer.Execute("BEGIN IMMEDIATE")
+			fatalOnError(t, e, "unable to lock database")
+		}
+
+		lock()
+		attempts := 0
+		fatalOnError(t, db.SetBusyHandler(func(attempt int) bool {
+			attempts = attempt
+			return attempt < 3
+		}), "unable to set busy handler")
+		if _, e := db.Execute("INSERT INTO foo VALUES (1, 'blocked')"); !errors.Is(e, BUSY) {
+			t.Fatalf("expected %v, got %v", BUSY, e)
+		}
+		if attempts != 3 {
+			t.Fatalf("expected 3 attempts, got %v", attempts)
+		}
+
+		fatalOnError(t, db.SetBusyHandler(func(int) bool { panic("busy") }), "unable to set panicking busy handler")
+		if _, e := db.Execute("INSERT INTO foo VALUES (1, 'panicked')"); !errors.Is(e, BUSY) {
+			t.Fatalf("expected %v, got %v", BUSY, e)
+		}
+
+		fatalOnError(t, db.SetBusyHandler(Backoff(time.Millisecond, 10 * time.Millisecond, 100)), "unable to set backoff handler")
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			writer.Commit()
+		}()
+		fatalOnError(t, db.Begin(), "unable to begin transaction")
+		_, e = db.Execute("INSERT INTO foo VALUES (2, 'retried')")
+		fatalOnError(t, e, "insert not retried")
+		fatalOnError(t, db.Commit(), "unable to commit")
+
+		lock()
+		fatalOnError(t, db.SetBusyTimeout(10 * time.Millisecond), "unable to set busy timeout")
+		start := time.Now()
+		if _, e := db.Execute("INSERT INTO foo VALUES (3, 'timed out')"); !errors.Is(e, BUSY) {
+			t.Fatalf("expected %v, got %v", BUSY, e)
+		}
+		if d := time.Since(start); d < 10 * time.Millisecond {
+			t.Fatalf("busy timeout returned after %v", d)
+		}
+		fatalOnError(t, writer.Rollback(), "unable to unlock database")
+	})
+}
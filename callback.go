@@ -0,0 +1,57 @@
+package sqlite3
+
+// #include <stdint.h>
+import "C"
+import (
+	"runtime/cgo"
+	"unsafe"
+)
+
+// Go values handed to SQLite as user data pointers are wrapped in a
+// cgo.Handle. The handle is passed through C as a uintptr_t and released by
+// gosqlite3_release when SQLite destroys the user data.
+
+func newHandle(v interface{}) C.uintptr_t {
+	return C.uintptr_t(cgo.NewHandle(v))
+}
+
+func handleValue(p unsafe.Pointer) interface{} {
+	return cgo.Handle(uintptr(p)).Value()
+}
+
+// setHook registers `v` as the Go side of the connection-level callback
+// `name`. SQLite offers no destructor for these callbacks, so the handle is
+// kept on the Database and released when the hook is replaced or the
+// database is closed. `register` is called with the new handle, or 0 when
+// `v` is nil, before the previous handle is released.
+func (db *Database) setHook(name string, v interface{}, register func(h C.uintptr_t)) {
+	var h C.uintptr_t
+	if v != nil {
+		h = newHandle(v)
+	}
+	register(h)
+	if old, ok := db.hooks[name]; ok {
+		cgo.Handle(old).Delete()
+		delete(db.hooks, name)
+	}
+	if h != 0 {
+		if db.hooks == nil {
+			db.hooks = make(map[string]C.uintptr_t)
+		}
+		db.hooks[name] = h
+	}
+}
+
+// releaseHooks releases the handles of all hooks once the connection has
+// been closed.
+func (db *Database) releaseHooks() {
+	for name, h := range db.hooks {
+		cgo.Handle(h).Delete()
+		delete(db.hooks, name)
+	}
+}
+
+//export gosqlite3_release
+func gosqlite3_release(p unsafe.Pointer) {
+	cgo.Handle(uintptr(p)).Delete()
+}
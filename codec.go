@@ -0,0 +1,201 @@
+package sqlite3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Codec encodes values which have no native SQLite3 representation into
+// BLOBs and decodes them again.
+type Codec interface {
+	Encode(value interface{}) ([]byte, error)
+	Decode(data []byte, dest interface{}) error
+}
+
+// TypeDecoder is implemented by codecs which record the type of encoded
+// values, such as those returned by Tagged. Scanning a BLOB into an
+// *interface{} with such a codec yields the reconstructed value, or the
+// BLOB itself if DecodeValue fails with an error matching ErrUntagged.
+type TypeDecoder interface {
+	DecodeValue(data []byte) (interface{}, error)
+}
+
+// ErrUntagged is returned by DecodeValue for data which carries no type
+// tag, or the tag of a type which has not been registered.
+var ErrUntagged = errors.New("sqlite3: missing or unregistered type tag")
+
+var (
+	GobCodec Codec =	gobCodec{}
+	JSONCodec Codec =	jsonCodec{}
+)
+
+type gobCodec struct{}
+
+func (gobCodec) Encode(value interface{}) ([]byte, error) {
+	return gobEncode(value)
+}
+
+func (gobCodec) Decode(data []byte, dest interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(dest)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec) Decode(data []byte, dest interface{}) error {
+	return json.Unmarshal(data, dest)
+}
+
+// gobEncode encodes values which have no native SQLite3 representation.
+func gobEncode(value interface{}) (b []byte, e error) {
+	buffer := new(bytes.Buffer)
+	if gob.NewEncoder(buffer).Encode(value) != nil {
+		e = ENCODER
+	} else {
+		b = buffer.Bytes()
+	}
+	return
+}
+
+type encodedValue struct {
+	codec		Codec
+	value		interface{}
+}
+
+// Encoded wraps `value` so that it is bound or returned from a function as
+// a BLOB encoded with `codec`.
+func Encoded(codec Codec, value interface{}) interface{} {
+	return encodedValue{codec, value}
+}
+
+// Gob wraps `value` so that it is bound or returned from a function as a
+// gob-encoded BLOB, which can be read back with GobDecode.
+func Gob(value interface{}) interface{} {
+	return Encoded(GobCodec, value)
+}
+
+// GobDecode decodes a BLOB stored with Gob into the value pointed to by
+// `dest`.
+func GobDecode(blob []byte, dest interface{}) error {
+	return GobCodec.Decode(blob, dest)
+}
+
+// SetCodec sets the codec used to store values which have no native SQLite3
+// representation and to scan BLOB and TEXT columns into destinations which
+// are not supported natively. Passing nil removes it.
+//
+// Codecs are not synchronized with statements using them, so they should be
+// set before the database is shared between goroutines.
+func (db *Database) SetCodec(c Codec) {
+	db.codec = c
+}
+
+// SetColumnCodec sets the codec used instead of the database codec for
+// result columns named `column` and for parameters of the same name, such
+// as :column. Passing nil removes it. As with SetCodec, column codecs should
+// be set before the database is shared between goroutines.
+func (db *Database) SetColumnCodec(column string, c Codec) {
+	if db.codecs == nil {
+		db.codecs = make(map[string]Codec)
+	}
+	if c == nil {
+		delete(db.codecs, strings.ToLower(column))
+	} else {
+		db.codecs[strings.ToLower(column)] = c
+	}
+}
+
+// codecFor returns the codec for the column `name`.
+func (db *Database) codecFor(name string) Codec {
+	if c, ok := db.codecs[strings.ToLower(name)]; ok {
+		return c
+	}
+	return db.codec
+}
+
+var taggedTypes sync.Map
+
+// RegisterType records the type of `value` so that values of that type
+// encoded by a Tagged codec in another process can be reconstructed.
+// Values encoded in the same process are registered automatically. A nil
+// `value` has no type and is ignored.
+func RegisterType(value interface{}) {
+	if t := reflect.TypeOf(value); t != nil {
+		taggedTypes.Store(typeName(t), t)
+	}
+}
+
+func typeName(t reflect.Type) string {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
+
+type taggedCodec struct {
+	Codec
+}
+
+// Tagged returns a codec which prefixes the data encoded by `c` with the
+// name of the encoded value's type, allowing the value to be reconstructed
+// without knowing its type in advance.
+func Tagged(c Codec) Codec {
+	return taggedCodec{c}
+}
+
+func (c taggedCodec) Encode(value interface{}) (data []byte, e error) {
+	t := reflect.TypeOf(value)
+	if t == nil {
+		return nil, errors.New("sqlite3: can not encode untyped nil")
+	}
+	name := typeName(t)
+	taggedTypes.LoadOrStore(name, t)
+	var payload []byte
+	if payload, e = c.Codec.Encode(value); e == nil {
+		data = binary.AppendUvarint(nil, uint64(len(name)))
+		data = append(append(data, name...), payload...)
+	}
+	return
+}
+
+func (c taggedCodec) split(data []byte) (name string, payload []byte, e error) {
+	n, k := binary.Uvarint(data)
+	if k <= 0 || uint64(len(data) - k) < n {
+		return "", nil, ErrUntagged
+	}
+	return string(data[k:k + int(n)]), data[k + int(n):], nil
+}
+
+func (c taggedCodec) Decode(data []byte, dest interface{}) (e error) {
+	var payload []byte
+	if _, payload, e = c.split(data); e == nil {
+		e = c.Codec.Decode(payload, dest)
+	}
+	return
+}
+
+func (c taggedCodec) DecodeValue(data []byte) (value interface{}, e error) {
+	name, payload, e := c.split(data)
+	if e != nil {
+		return
+	}
+	t, ok := taggedTypes.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUntagged, name)
+	}
+	v := reflect.New(t.(reflect.Type))
+	if e = c.Codec.Decode(payload, v.Interface()); e == nil {
+		value = v.Elem().Interface()
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package sqlite3
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type Settings struct {
+	Theme		string
+	Volume		int
+}
+
+func TestCodecs(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		db.runQuery(t, "CREATE TABLE users (id INTEGER, settings BLOB, tags BLOB)")
+		st, _ := db.Prepare("INSERT INTO users VALUES (:id, :settings, :tags)")
+		fatalOnSuccess(t, st.BindNamed(map[string]interface{}{"id": 1, "settings": Settings{"dark", 3}, "tags": []string{"a"}}), "bound struct without codec")
+		st.Finalize()
+
+		db.SetCodec(GobCodec)
+		db.SetColumnCodec("settings", JSONCodec)
+		st, _ = db.Prepare("INSERT INTO users VALUES (:id, :settings, :tags)")
+		fatalOnError(t, st.BindNamed(map[string]interface{}{"id": 1, "settings": Settings{"dark", 3}, "tags": []string{"a", "b"}}), "unable to bind")
+		st.Step()
+		st.Finalize()
+		if v := db.queryValue(t, "SELECT json_extract(settings, '$.Theme') FROM users"); v != "dark" {
+			t.Fatalf("settings not stored as JSON: %v", v)
+		}
+
+		var u struct {
+			ID			int
+			Settings	Settings
+			Tags		[]string
+		}
+		fatalOnError(t, db.Get(&u, "SELECT * FROM users"), "unable to get")
+		if u.Settings.Volume != 3 || !reflect.DeepEqual(u.Tags, []string{"a", "b"}) {
+			t.Fatalf("unexpected decoded values %+v", u)
+		}
+		var s *Settings
+		fatalOnError(t, db.Get(&s, "SELECT settings FROM users"), "unable to get pointer")
+		if s == nil || s.Theme != "dark" {
+			t.Fatalf("unexpected settings %v", s)
+		}
+
+		if v := db.queryValue(t, "SELECT typeof(?)", Encoded(JSONCodec, map[string]int{"a": 1})); v != "blob" {
+			t.Fatalf("encoded value bound as %v", v)
+		}
+	})
+}
+
+func TestTaggedCodec(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		db.SetCodec(Tagged(GobCodec))
+		db.runQuery(t, "CREATE TABLE things (value BLOB)")
+		db.runQuery(t, "INSERT INTO things VALUES (?)", Settings{"light", 7})
+		db.runQuery(t, "INSERT INTO things VALUES (?)", []int{1, 2})
+		db.runQuery(t, "INSERT INTO things VALUES (?)", []byte{1, 2, 3})
+
+		var values []interface{}
+		fatalOnError(t, db.Select(&values, "SELECT value FROM things"), "unable to select")
+		if len(values) != 3 || values[0] != (Settings{"light", 7}) || !reflect.DeepEqual(values[1], []int{1, 2}) || !reflect.DeepEqual(values[2], []byte{1, 2, 3}) {
+			t.Fatalf("unexpected values %#v", values)
+		}
+
+		var s Settings
+		fatalOnError(t, db.Get(&s, "SELECT value FROM things LIMIT 1"), "unable to decode into typed destination")
+		if s.Volume != 7 {
+			t.Fatalf("unexpected settings %v", s)
+		}
+
+		if _, e := Tagged(JSONCodec).(TypeDecoder).DecodeValue([]byte{5, 'x'}); !errors.Is(e, ErrUntagged) {
+			t.Fatalf("decoded truncated type tag: %v", e)
+		}
+		RegisterType(nil)
+	})
+}
@@ -0,0 +1,165 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdint.h>
+// #include <stdlib.h>
+// extern int gosqlite3_compare(uintptr_t, int, char*, int, char*);
+// extern void gosqlite3_collation_needed(uintptr_t, sqlite3*, char*);
+// extern void gosqlite3_release(void*);
+// static int gosqlite3_collate(void* p, int na, const void* a, int nb, const void* b) {
+//     return gosqlite3_compare((uintptr_t)p, na, (char*)a, nb, (char*)b);
+// }
+// static void gosqlite3_needed(void* p, sqlite3* db, int rep, const char* name) {
+//     gosqlite3_collation_needed((uintptr_t)p, db, (char*)name);
+// }
+// static int gosqlite3_create_collation(sqlite3* db, const char* name, uintptr_t h) {
+//     return sqlite3_create_collation_v2(db, name, SQLITE_UTF8, (void*)h, gosqlite3_collate, gosqlite3_release);
+// }
+// static int gosqlite3_collation_needed_hook(sqlite3* db, uintptr_t h) {
+//     return sqlite3_collation_needed(db, (void*)h, h ? gosqlite3_needed : NULL);
+// }
+import "C"
+import (
+	"runtime/cgo"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// Collation compares two strings, returning a negative number, zero or a
+// positive number when `a` sorts before, equal to or after `b`.
+type Collation func(a, b string) int
+
+// Collations holds the collation sequences which are registered with every
+// database when it is opened. As NATURAL is an SQL keyword it has to be
+// quoted when used, as in `ORDER BY name COLLATE "NATURAL"`.
+var Collations = map[string]Collation{
+	"NATURAL":			NaturalCompare,
+	"UNICODE_NOCASE":	UnicodeNoCaseCompare,
+}
+
+func (db *Database) createBuiltinCollations() (e error) {
+	for name, cmp := range Collations {
+		if e = db.CreateCollation(name, cmp); e != nil {
+			break
+		}
+	}
+	return
+}
+
+// CreateCollation registers `cmp` as the collation sequence `name`. Strings
+// for which `cmp` panics are treated as equal.
+func (db *Database) CreateCollation(name string, cmp func(a, b string) int) (e error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	h := newHandle(Collation(cmp))
+	if e = SQLiteError(C.gosqlite3_create_collation(db.handle, cname, h)); e != nil {
+		cgo.Handle(h).Delete()
+	}
+	return
+}
+
+type collationNeeded struct {
+	db		*Database
+	f		func(db *Database, name string)
+}
+
+// CollationNeeded registers `f` to be called whenever a statement requires a
+// collation sequence which has not been defined, giving `f` the opportunity
+// to call CreateCollation. Passing nil removes the callback. Panics in `f`
+// are recovered and ignored.
+func (db *Database) CollationNeeded(f func(db *Database, name string)) (e error) {
+	var v interface{}
+	if f != nil {
+		v = &collationNeeded{db: db, f: f}
+	}
+	db.setHook("collation_needed", v, func(h C.uintptr_t) {
+		e = SQLiteError(C.gosqlite3_collation_needed_hook(db.handle, h))
+	})
+	return
+}
+
+//export gosqlite3_compare
+func gosqlite3_compare(h C.uintptr_t, na C.int, a *C.char, nb C.int, b *C.char) (r C.int) {
+	defer func() {
+		if recover() != nil {
+			r = 0
+		}
+	}()
+	cmp := cgo.Handle(h).Value().(Collation)
+	switch r := cmp(C.GoStringN(a, na), C.GoStringN(b, nb)); {
+	case r < 0:
+		return -1
+	case r > 0:
+		return 1
+	}
+	return 0
+}
+
+//export gosqlite3_collation_needed
+func gosqlite3_collation_needed(h C.uintptr_t, handle *C.sqlite3, name *C.char) {
+	defer func() { recover() }()
+	c := cgo.Handle(h).Value().(*collationNeeded)
+	c.f(c.db, C.GoString(name))
+}
+
+// NaturalCompare orders strings so that runs of digits are compared by
+// their numeric value, placing "file2" before "file10".
+func NaturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			var da, db string
+			da, a = splitDigits(a)
+			db, b = splitDigits(b)
+			ta, tb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			switch {
+			case len(ta) != len(tb):
+				return len(ta) - len(tb)
+			case ta != tb:
+				return strings.Compare(ta, tb)
+			case len(da) != len(db):
+				return len(db) - len(da)
+			}
+			continue
+		}
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			return int(ra) - int(rb)
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return len(a) - len(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func splitDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// UnicodeNoCaseCompare orders strings by code point after Unicode simple
+// case folding, so that "ÄPFEL" and "äpfel" are equal.
+func UnicodeNoCaseCompare(a, b string) int {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra, rb = foldRune(ra), foldRune(rb); ra != rb {
+			return int(ra) - int(rb)
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return len(a) - len(b)
+}
+
+// foldRune maps every rune in a case folding orbit to the same rune.
+func foldRune(r rune) rune {
+	return unicode.ToLower(unicode.ToUpper(r))
+}
@@ -0,0 +1,75 @@
+package sqlite3
+
+import (
+	"strings"
+	"testing"
+)
+
+func (db *Database) sortedText(t *testing.T, collation string) (values []string) {
+	_, e := db.Execute("SELECT text FROM foo ORDER BY text COLLATE \"" + collation + "\"", func(s *Statement, v ...interface{}) {
+		values = append(values, v[0].(string))
+	})
+	fatalOnError(t, e, "unable to sort with collation %v", collation)
+	return
+}
+
+func TestCollations(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		FOO.Create(db)
+		for i, v := range []string{"file10", "File2", "file2", "file1", "äpfel", "ÄPFEL"} {
+			db.runQuery(t, "INSERT INTO foo VALUES (?, ?)", i, v)
+		}
+
+		if v := strings.Join(db.sortedText(t, "NATURAL"), ","); v != "File2,file1,file2,file10,ÄPFEL,äpfel" {
+			t.Fatalf("NATURAL ordering: %v", v)
+		}
+		if v := db.queryValue(t, "SELECT COUNT(DISTINCT text COLLATE UNICODE_NOCASE) FROM foo"); v != int64(4) {
+			t.Fatalf("UNICODE_NOCASE found %v distinct values", v)
+		}
+
+		fatalOnError(t, db.CreateCollation("LENGTH", func(a, b string) int {
+			return len(a) - len(b)
+		}), "unable to create LENGTH collation")
+		if v := db.sortedText(t, "LENGTH"); len(v[0]) != 5 || len(v[5]) != 6 {
+			t.Fatalf("LENGTH ordering: %v", v)
+		}
+
+		requested := ""
+		fatalOnError(t, db.CollationNeeded(func(db *Database, name string) {
+			requested = name
+			db.CreateCollation(name, func(a, b string) int {
+				return strings.Compare(b, a)
+			})
+		}), "unable to register CollationNeeded")
+		if v := db.sortedText(t, "REVERSE"); requested != "REVERSE" || v[0] != "äpfel" {
+			t.Fatalf("lazily provided REVERSE ordering: %v", v)
+		}
+		fatalOnError(t, db.CreateCollation("PANIC", func(a, b string) int { panic("compare") }), "unable to create PANIC collation")
+		if v := db.sortedText(t, "PANIC"); len(v) != 6 {
+			t.Fatalf("PANIC ordering: %v", v)
+		}
+		fatalOnError(t, db.CollationNeeded(func(*Database, string) { panic("needed") }), "unable to register panicking CollationNeeded")
+		if _, e := db.Execute("SELECT text FROM foo ORDER BY text COLLATE UNKNOWN"); e == nil {
+			t.Fatal("collation provided by panicking callback")
+		}
+		fatalOnError(t, db.CollationNeeded(nil), "unable to remove CollationNeeded")
+		if _, e := db.Execute("SELECT text FROM foo ORDER BY text COLLATE MISSING"); e == nil {
+			t.Fatal("unknown collation accepted")
+		}
+	})
+}
+
+func TestNaturalCompare(t *testing.T) {
+	for _, c := range []struct{ a, b string; r int }{
+		{"a2", "a10", -1},
+		{"a02", "a2", -1},
+		{"a10b", "a10a", 1},
+		{"", "a", -1},
+		{"x9y", "x9y", 0},
+	} {
+		r := NaturalCompare(c.a, c.b)
+		if (r < 0 && c.r >= 0) || (r > 0 && c.r <= 0) || (r == 0 && c.r != 0) {
+			t.Fatalf("NaturalCompare(%q, %q) = %v", c.a, c.b, r)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package sqlite3
+
+import "strings"
+
+// ConstraintError describes a violated UNIQUE, PRIMARY KEY, NOT NULL, CHECK
+// or FOREIGN KEY constraint.
+//
+// Kind holds the extended result code identifying the constraint, one of
+// CONSTRAINT_UNIQUE, CONSTRAINT_PRIMARYKEY, CONSTRAINT_NOTNULL,
+// CONSTRAINT_CHECK or CONSTRAINT_FOREIGNKEY. Table and Columns name the
+// columns involved for UNIQUE, PRIMARY KEY and NOT NULL violations. Name is
+// the name or expression of a failed CHECK constraint, or the name of a
+// unique index on expressions. The engine does not report which foreign key
+// failed, so all three are empty for FOREIGN KEY violations.
+type ConstraintError struct {
+	Err			*Error
+	Kind		Errno
+	Table		string
+	Columns		[]string
+	Name		string
+}
+
+func (c *ConstraintError) Error() string {
+	return c.Err.Error()
+}
+
+// Unwrap returns the underlying *Error.
+func (c *ConstraintError) Unwrap() error {
+	return c.Err
+}
+
+// constraintError returns a *ConstraintError for constraint violations
+// which can be described in more detail, and `e` otherwise.
+func constraintError(e *Error) error {
+	switch e.ExtendedCode {
+	case CONSTRAINT_UNIQUE, CONSTRAINT_PRIMARYKEY, CONSTRAINT_NOTNULL, CONSTRAINT_CHECK, CONSTRAINT_FOREIGNKEY:
+	default:
+		return e
+	}
+	c := &ConstraintError{Err: e, Kind: e.ExtendedCode}
+	_, detail, ok := strings.Cut(e.Message, "constraint failed: ")
+	switch {
+	case !ok:
+	case c.Kind == CONSTRAINT_CHECK:
+		c.Name = detail
+	case strings.HasPrefix(detail, "index '"):
+		c.Name = strings.TrimSuffix(strings.TrimPrefix(detail, "index '"), "'")
+	default:
+		for _, column := range strings.Split(detail, ", ") {
+			table, name, _ := strings.Cut(column, ".")
+			c.Table = table
+			c.Columns = append(c.Columns, name)
+		}
+	}
+	return c
+}
@@ -0,0 +1,57 @@
+package sqlite3
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConstraintError(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		db.runQuery(t, "PRAGMA foreign_keys = ON")
+		db.runQuery(t, "CREATE TABLE teams (id INTEGER PRIMARY KEY)")
+		db.runQuery(t, `CREATE TABLE people (
+			id INTEGER PRIMARY KEY,
+			first TEXT NOT NULL,
+			last TEXT,
+			age INTEGER CONSTRAINT adult CHECK (age >= 18),
+			team INTEGER REFERENCES teams(id),
+			UNIQUE (first, last)
+		)`)
+		db.runQuery(t, "INSERT INTO people VALUES (1, 'Ada', 'Lovelace', 36, NULL)")
+
+		for _, c := range []struct {
+			sql			string
+			kind		Errno
+			table		string
+			columns		[]string
+			name		string
+		}{
+			{"INSERT INTO people VALUES (2, 'Ada', 'Lovelace', 36, NULL)", CONSTRAINT_UNIQUE, "people", []string{"first", "last"}, ""},
+			{"INSERT INTO people VALUES (1, 'Alan', 'Turing', 41, NULL)", CONSTRAINT_PRIMARYKEY, "people", []string{"id"}, ""},
+			{"INSERT INTO people VALUES (2, NULL, 'Turing', 41, NULL)", CONSTRAINT_NOTNULL, "people", []string{"first"}, ""},
+			{"INSERT INTO people VALUES (2, 'Alan', 'Turing', 12, NULL)", CONSTRAINT_CHECK, "", nil, "adult"},
+			{"INSERT INTO people VALUES (2, 'Alan', 'Turing', 41, 7)", CONSTRAINT_FOREIGNKEY, "", nil, ""},
+		} {
+			_, e := db.Execute(c.sql)
+			var ce *ConstraintError
+			if !errors.As(e, &ce) {
+				t.Fatalf("%v: expected *ConstraintError, got %v", c.sql, e)
+			}
+			if ce.Kind != c.kind || ce.Table != c.table || !reflect.DeepEqual(ce.Columns, c.columns) || ce.Name != c.name {
+				t.Fatalf("%v: unexpected constraint error %+v", c.sql, ce)
+			}
+			var err *Error
+			if !errors.Is(e, CONSTRAINT) || !errors.Is(e, c.kind) || !errors.As(e, &err) || err.SQL != c.sql {
+				t.Fatalf("%v: constraint error does not wrap *Error: %v", c.sql, e)
+			}
+		}
+
+		db.runQuery(t, "CREATE UNIQUE INDEX people_initials ON people (substr(first, 1, 1), substr(last, 1, 1))")
+		_, e := db.Execute("INSERT INTO people VALUES (2, 'Alfred', 'Lord', 41, NULL)")
+		var ce *ConstraintError
+		if !errors.As(e, &ce) || ce.Kind != CONSTRAINT_UNIQUE || ce.Name != "people_initials" || ce.Columns != nil {
+			t.Fatalf("unexpected expression index violation %v", e)
+		}
+	})
+}
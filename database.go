@@ -1,12 +1,14 @@
 package sqlite3
 
 // #include <sqlite3.h>
+// #include <stdint.h>
 // #include <stdlib.h>
 // int gosqlite3_prepare_v2(sqlite3* db, const char* zSql, int nByte, sqlite3_stmt **ppStmt) {
 //     return sqlite3_prepare_v2(db, zSql, nByte, ppStmt, NULL);
 // }
 import "C"
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -77,6 +79,10 @@ type Database struct {
 	Filename   string
 	DBFlag
 	Savepoints []interface{}
+	hooks      map[string]C.uintptr_t
+	cache      *statementCache
+	codec      Codec
+	codecs     map[string]Codec
 }
 
 // TransientDatabase returns a handle to an in-memory database.
@@ -117,19 +123,27 @@ func (db *Database) Open(flags ...DBFlag) (e error) {
 
 		cs := C.CString(db.Filename)
 		defer C.free(unsafe.Pointer(cs))
-		e = SQLiteError(C.sqlite3_open_v2(cs, &db.handle, C.int(db.DBFlag), nil))
+		e = db.lastError(C.sqlite3_open_v2(cs, &db.handle, C.int(db.DBFlag), nil), "")
 
 		if e == nil && db.handle == nil {
 			e = CANTOPEN
 		}
+		if e == nil {
+			e = db.createBuiltinCollations()
+		}
+		if e != nil && db.handle != nil {
+			db.Close()
+		}
 	}
 	return
 }
 
 // Close is used to close the database.
 func (db *Database) Close() {
+	db.cache.flush()
 	C.sqlite3_close(db.handle)
 	db.handle = nil
+	db.releaseHooks()
 }
 
 // LastInsertRowID returns the id of the most recently successful INSERT.
@@ -171,10 +185,9 @@ func (db *Database) TotalChanges() int {
 	return int(C.sqlite3_total_changes(db.handle))
 }
 
-// Error returns the numeric result code for the most recently failed database
-// call.
+// Error returns the error for the most recently failed database call.
 func (db *Database) Error() error {
-	return SQLiteError(C.sqlite3_errcode(db.handle))
+	return db.lastError(C.sqlite3_errcode(db.handle), "")
 }
 
 // Prepare compiles the SQL query into a byte-code program and binds the 
@@ -183,7 +196,7 @@ func (db *Database) Prepare(sql string, values ...interface{}) (s *Statement, e
 	s = &Statement{db: db, timestamp: time.Now().UnixNano()}
 	cs := C.CString(sql)
 	defer C.free(unsafe.Pointer(cs))
-	if e = SQLiteError(C.gosqlite3_prepare_v2(db.handle, cs, -1, &s.cptr)); e != nil {
+	if e = db.lastError(C.gosqlite3_prepare_v2(db.handle, cs, -1, &s.cptr), sql); e != nil {
 		s = nil
 	} else {
 		if len(values) > 0 {
@@ -193,10 +206,10 @@ func (db *Database) Prepare(sql string, values ...interface{}) (s *Statement, e
 	return
 }
 
-// Execute runs the SQL statement. 
+// Execute runs the SQL statement, using the statement cache if enabled.
 func (db *Database) Execute(sql string, f ...func(*Statement, ...interface{})) (c int, e error) {
 	var st *Statement
-	if st, e = db.Prepare(sql); e == nil {
+	if st, e = db.prepareCached(sql); e == nil {
 		c, e = st.All(f...)
 	}
 	return
@@ -343,7 +356,7 @@ func (db *Database) Backup(p BackupParameters) (r Reporter, e error) {
 						Verbose:	p.Verbose,
 					}
 					r <- report
-					if e := report.Error; !(e == nil || e == BUSY || e == LOCKED) {
+					if e := report.Error; !(e == nil || errors.Is(e, BUSY) || errors.Is(e, LOCKED)) {
 						break
 					}
 					if p.Interval > 0 {
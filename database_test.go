@@ -20,10 +20,10 @@ func (db *Database) createTestData(t *testing.T, repeats int) {
 	for i := 0; i < repeats; i++ {
 		if i % 2 == 0 {
 			db.runQuery(t, "INSERT INTO foo values (?, 'holy moly')", i)
-			db.runQuery(t, "INSERT INTO bar values (?, ?)", i, TwoItems{ "holy moly", "guacomole" })
+			db.runQuery(t, "INSERT INTO bar values (?, ?)", i, Gob(TwoItems{ "holy moly", "guacomole" }))
 		} else {
 			db.runQuery(t, "INSERT INTO foo values (?, 'guacomole')", i)
-			db.runQuery(t, "INSERT INTO bar values (?, ?)", i, TwoItems{ "guacomole", "holy moly" })
+			db.runQuery(t, "INSERT INTO bar values (?, ?)", i, Gob(TwoItems{ "guacomole", "holy moly" }))
 		}
 	}
 	db.runQuery(t, "PRAGMA synchronous=NORMAL")
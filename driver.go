@@ -0,0 +1,398 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdlib.h>
+// int gosqlite3_prepare_tail(sqlite3* db, const char* zSql, int nByte, sqlite3_stmt **ppStmt, int *nTail) {
+//     const char *tail = NULL;
+//     int rv = sqlite3_prepare_v2(db, zSql, nByte, ppStmt, &tail);
+//     *nTail = tail == NULL ? 0 : (int)(tail - zSql);
+//     return rv;
+// }
+import "C"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+func init() {
+	sql.Register("sqlite3", &Driver{})
+}
+
+// TimeFormat is the layout used to store time.Time values passed through
+// the database/sql interface.
+const TimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+var timeFormats = []string{
+	TimeFormat,
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Driver implements the database/sql/driver interfaces on top of Database
+// and is registered with database/sql under the name "sqlite3".
+//
+// The data source name is passed verbatim to Open, so both plain filenames
+// and "file:" URIs are accepted.
+type Driver struct{}
+
+// Open returns a new connection to the database named by dsn.
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	flags := []DBFlag{O_FULLMUTEX, O_READWRITE, O_CREATE}
+	if strings.HasPrefix(dsn, "file:") {
+		flags = append(flags, O_URI)
+	}
+	db, e := Open(dsn, flags...)
+	if e != nil {
+		return nil, e
+	}
+	return &conn{db: db}, nil
+}
+
+type conn struct {
+	db	*Database
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if e := ctx.Err(); e != nil {
+		return nil, e
+	}
+	s, e := c.db.Prepare(query)
+	if e != nil {
+		return nil, e
+	}
+	return &stmt{s: s}, nil
+}
+
+func (c *conn) Close() error {
+	c.db.Close()
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts a transaction. SQLite transactions are always
+// serializable; read-only transactions are enforced with PRAGMA query_only.
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault, sql.LevelSerializable:
+	default:
+		return nil, errors.New("sqlite3: unsupported isolation level")
+	}
+	if e := ctx.Err(); e != nil {
+		return nil, e
+	}
+	if opts.ReadOnly {
+		if _, e := c.db.Execute("PRAGMA query_only = 1"); e != nil {
+			return nil, e
+		}
+	}
+	if e := c.db.Begin(); e != nil {
+		if opts.ReadOnly {
+			c.db.Execute("PRAGMA query_only = 0")
+		}
+		return nil, e
+	}
+	return &tx{c: c, readonly: opts.ReadOnly}, nil
+}
+
+// ExecContext runs the query without returning any rows. When no arguments
+// are supplied every statement in query is executed in turn, which allows
+// migration scripts to be run in a single call.
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	r := driver.Result(&result{c.db.LastInsertRowID(), int64(c.db.Changes())})
+	for strings.TrimSpace(query) != "" {
+		if e := ctx.Err(); e != nil {
+			return nil, e
+		}
+		s, tail, e := c.prepareTail(query)
+		if e != nil {
+			return nil, e
+		}
+		query = tail
+		if s == nil {
+			continue
+		}
+		var use []driver.NamedValue
+		if use, args, e = splitArgs(s, args, strings.TrimSpace(tail) != ""); e != nil {
+			s.Finalize()
+			return nil, e
+		}
+		st := &stmt{s: s}
+		r, e = st.ExecContext(ctx, use)
+		st.Close()
+		if e != nil {
+			return nil, e
+		}
+	}
+	return r, nil
+}
+
+// splitArgs returns the arguments for the statement `s` and those left for
+// the statements which follow it. When `more` statements follow, `s` takes
+// as many positional arguments as it has parameters and the rest are
+// renumbered from 1, so that the last statement takes all remaining
+// arguments. Named arguments can only be used with a single statement.
+func splitArgs(s *Statement, args []driver.NamedValue, more bool) (use, rest []driver.NamedValue, e error) {
+	if !more {
+		return args, nil, nil
+	}
+	for _, v := range args {
+		if v.Name != "" {
+			return nil, nil, errors.New("sqlite3: named arguments can not be used with multiple statements")
+		}
+	}
+	n := s.Parameters()
+	if n > len(args) {
+		n = len(args)
+	}
+	use = args[:n]
+	for i, v := range args[n:] {
+		v.Ordinal = i + 1
+		rest = append(rest, v)
+	}
+	return
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	st, e := c.PrepareContext(ctx, query)
+	if e != nil {
+		return nil, e
+	}
+	r, e := st.(*stmt).QueryContext(ctx, args)
+	if e != nil {
+		st.Close()
+		return nil, e
+	}
+	r.(*rows).finalize = true
+	return r, nil
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	return checkNamedValue(nv)
+}
+
+// prepareTail compiles the first statement in query and returns the
+// remaining uncompiled text. A nil Statement is returned when the first
+// statement is empty or query holds only whitespace or comments.
+func (c *conn) prepareTail(query string) (s *Statement, tail string, e error) {
+	var n C.int
+	s = &Statement{db: c.db, timestamp: time.Now().UnixNano()}
+	cs := C.CString(query)
+	defer C.free(unsafe.Pointer(cs))
+	if e = c.db.lastError(C.gosqlite3_prepare_tail(c.db.handle, cs, -1, &s.cptr, &n), query); e != nil {
+		return nil, "", e
+	}
+	if tail = query[int(n):]; s.cptr == nil {
+		s = nil
+	}
+	return
+}
+
+type tx struct {
+	c			*conn
+	readonly	bool
+}
+
+func (t *tx) Commit() (e error) {
+	e = t.c.db.Commit()
+	t.done()
+	return
+}
+
+func (t *tx) Rollback() (e error) {
+	e = t.c.db.Rollback()
+	t.done()
+	return
+}
+
+func (t *tx) done() {
+	if t.readonly {
+		t.c.db.Execute("PRAGMA query_only = 0")
+	}
+}
+
+type result struct {
+	id			int64
+	changes		int64
+}
+
+func (r *result) LastInsertId() (int64, error) {
+	return r.id, nil
+}
+
+func (r *result) RowsAffected() (int64, error) {
+	return r.changes, nil
+}
+
+type stmt struct {
+	s	*Statement
+}
+
+func (st *stmt) Close() error {
+	return st.s.Finalize()
+}
+
+func (st *stmt) NumInput() int {
+	return st.s.Parameters()
+}
+
+func (st *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return st.ExecContext(context.Background(), namedValues(args))
+}
+
+func (st *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return st.QueryContext(context.Background(), namedValues(args))
+}
+
+func (st *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if e := st.bind(ctx, args); e != nil {
+		return nil, e
+	}
+	var e error
+	for e = st.s.StepContext(ctx); e == ROW; e = st.s.StepContext(ctx) {
+	}
+	if e != nil {
+		st.s.Reset()
+		return nil, e
+	}
+	db := st.s.db
+	return &result{db.LastInsertRowID(), int64(db.Changes())}, nil
+}
+
+func (st *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if e := st.bind(ctx, args); e != nil {
+		return nil, e
+	}
+	r := &rows{ctx: ctx, s: st.s}
+	for i := 0; i < st.s.Columns(); i++ {
+		r.columns = append(r.columns, st.s.ColumnName(i))
+	}
+	return r, nil
+}
+
+func (st *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	return checkNamedValue(nv)
+}
+
+// bind resets the statement, discarding any error left over from a
+// previous execution, and binds args using the parameter name for
+// named arguments and the ordinal position otherwise.
+func (st *stmt) bind(ctx context.Context, args []driver.NamedValue) (e error) {
+	if e = ctx.Err(); e != nil {
+		return
+	}
+	st.s.Reset()
+	if e = st.s.ClearBindings(); e != nil {
+		return
+	}
+	for _, v := range args {
+		p := QueryParameter(v.Ordinal)
+		if v.Name != "" {
+			if p = QueryParameter(st.s.ParameterIndex(v.Name)); p == 0 {
+				return errors.New("sqlite3: unknown named parameter " + v.Name)
+			}
+		}
+		if e = p.Bind(st.s, v.Value); e != nil {
+			return
+		}
+	}
+	return
+}
+
+type rows struct {
+	ctx			context.Context
+	s			*Statement
+	columns		[]string
+	finalize	bool
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() (e error) {
+	if r.finalize {
+		e = r.s.Finalize()
+	} else {
+		e = r.s.Reset()
+	}
+	return
+}
+
+func (r *rows) Next(dest []driver.Value) (e error) {
+	switch e = r.s.StepContext(r.ctx); e {
+	case ROW:
+		for i := range dest {
+			dest[i] = driverValue(r.s, ResultColumn(i))
+		}
+		e = nil
+	case nil:
+		e = io.EOF
+	}
+	return
+}
+
+// ColumnTypeDatabaseTypeName returns the declared type of the column.
+func (r *rows) ColumnTypeDatabaseTypeName(i int) string {
+	return strings.ToUpper(C.GoString(C.sqlite3_column_decltype(r.s.cptr, C.int(i))))
+}
+
+func namedValues(args []driver.Value) (nv []driver.NamedValue) {
+	nv = make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return
+}
+
+// checkNamedValue accepts the types QueryParameter.Bind handles natively and
+// defers everything else to the database/sql default converter.
+func checkNamedValue(nv *driver.NamedValue) (e error) {
+	switch nv.Value.(type) {
+	case Valuer:
+	case driver.Valuer:
+		e = driver.ErrSkip
+	case nil, int, int64, float32, float64, string, []byte, bool, time.Time:
+	case int8, int16, int32, uint8, uint16, uint32:
+		nv.Value, e = driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	default:
+		e = driver.ErrSkip
+	}
+	return
+}
+
+// driverValue converts the column to one of the types permitted by
+// driver.Value. TEXT columns declared as DATE, DATETIME or TIMESTAMP are
+// parsed into time.Time when possible.
+func driverValue(s *Statement, c ResultColumn) (value driver.Value) {
+	switch c.Type(s) {
+	case TEXT:
+		text := c.Value(s).(string)
+		value = text
+		switch strings.ToUpper(C.GoString(C.sqlite3_column_decltype(s.cptr, C.int(c)))) {
+		case "DATE", "DATETIME", "TIMESTAMP":
+			if t, e := parseTime(text); e == nil {
+				value = t
+			}
+		}
+	default:
+		value = c.Value(s)
+	}
+	return
+}
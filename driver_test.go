@@ -0,0 +1,96 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func openTestDriver(t *testing.T) *sql.DB {
+	db, e := sql.Open("sqlite3", ":memory:")
+	fatalOnError(t, e, "unable to open :memory: through database/sql")
+	db.SetMaxOpenConns(1)
+	return db
+}
+
+func TestDriver(t *testing.T) {
+	db := openTestDriver(t)
+	defer db.Close()
+
+	_, e := db.Exec("CREATE TABLE foo (number INTEGER, text VARCHAR(20), data BLOB, stamp DATETIME); CREATE TABLE baz (id INTEGER);")
+	fatalOnError(t, e, "unable to run multi-statement schema")
+
+	now := time.Now().Round(time.Millisecond)
+	r, e := db.Exec("INSERT INTO foo VALUES (?, ?, ?, ?)", 1, "holy moly", []byte{1, 2, 3}, now)
+	fatalOnError(t, e, "unable to insert row")
+	if n, _ := r.RowsAffected(); n != 1 {
+		t.Fatalf("expected 1 row affected, got %v", n)
+	}
+	_, e = db.Exec("INSERT INTO foo VALUES (:n, :t, NULL, NULL)", sql.Named("n", 2), sql.Named("t", "guacomole"))
+	fatalOnError(t, e, "unable to insert row with named parameters")
+
+	_, e = db.Exec("INSERT INTO baz VALUES (?);; INSERT INTO baz VALUES (2); INSERT INTO baz VALUES (?) -- done", 1, 3)
+	fatalOnError(t, e, "unable to run multiple statements with arguments")
+	var ids int64
+	fatalOnError(t, db.QueryRow("SELECT SUM(id) FROM baz").Scan(&ids), "unable to sum ids")
+	if ids != 6 {
+		t.Fatalf("expected every statement to run, got sum %v", ids)
+	}
+	_, e = db.Exec("INSERT INTO baz VALUES (:id); INSERT INTO baz VALUES (:id)", sql.Named("id", 4))
+	fatalOnSuccess(t, e, "named arguments split across statements")
+	_, e = db.Exec("-- nothing to do")
+	fatalOnError(t, e, "unable to run comment")
+
+	var text string
+	var data []byte
+	var stamp time.Time
+	e = db.QueryRow("SELECT text, data, stamp FROM foo WHERE number = ?", 1).Scan(&text, &data, &stamp)
+	fatalOnError(t, e, "unable to query row")
+	if text != "holy moly" || len(data) != 3 || data[2] != 3 || !stamp.Equal(now) {
+		t.Fatalf("unexpected row: %v, %v, %v", text, data, stamp)
+	}
+
+	rows, e := db.QueryContext(context.Background(), "SELECT number FROM foo ORDER BY number")
+	fatalOnError(t, e, "unable to query rows")
+	c := 0
+	for rows.Next() {
+		var n int64
+		fatalOnError(t, rows.Scan(&n), "unable to scan row %v", c)
+		c++
+	}
+	rows.Close()
+	if c != 2 {
+		t.Fatalf("expected 2 rows, got %v", c)
+	}
+}
+
+func TestDriverTransactions(t *testing.T) {
+	db := openTestDriver(t)
+	defer db.Close()
+	_, e := db.Exec("CREATE TABLE foo (number INTEGER)")
+	fatalOnError(t, e, "unable to create table")
+
+	tx, e := db.Begin()
+	fatalOnError(t, e, "unable to begin transaction")
+	tx.Exec("INSERT INTO foo VALUES (1)")
+	fatalOnError(t, tx.Rollback(), "unable to rollback transaction")
+
+	tx, e = db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	fatalOnError(t, e, "unable to begin read-only transaction")
+	_, e = tx.Exec("INSERT INTO foo VALUES (2)")
+	fatalOnSuccess(t, e, "write succeeded in read-only transaction")
+	fatalOnError(t, tx.Commit(), "unable to commit transaction")
+
+	_, e = db.Exec("INSERT INTO foo VALUES (3)")
+	fatalOnError(t, e, "write failed after read-only transaction")
+
+	var c int
+	fatalOnError(t, db.QueryRow("SELECT COUNT(*) FROM foo").Scan(&c), "unable to count rows")
+	if c != 1 {
+		t.Fatalf("expected 1 row, got %v", c)
+	}
+
+	_, e = db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	fatalOnSuccess(t, e, "unsupported isolation level accepted")
+}
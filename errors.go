@@ -1,5 +1,6 @@
 package sqlite3
 
+// #include <sqlite3.h>
 import "C"
 import "fmt"
 
@@ -7,6 +8,9 @@ type Errno int
 
 func (e Errno) Error() (err string) {
 	if err = errText[e]; err == "" {
+		err = errText[e & 0xff]
+	}
+	if err == "" {
 		err = fmt.Sprintf("errno %v", int(e))
 	}
 	return
@@ -42,8 +46,83 @@ const (
 	NOTDB
 	ROW       = Errno(100)
 	DONE      = Errno(101)
-	ENCODER   = Errno(1000)
-	SAVEPOINT = Errno(1001)
+	ENCODER     = Errno(1000)
+	SAVEPOINT   = Errno(1001)
+	UNSUPPORTED = Errno(1002)
+)
+
+// Extended result codes refine the primary result code held in their lowest
+// eight bits.
+const (
+	ERROR_MISSING_COLLSEQ   = ERROR | 1<<8
+	ERROR_RETRY             = ERROR | 2<<8
+	ERROR_SNAPSHOT          = ERROR | 3<<8
+	IOERR_READ              = IOERR | 1<<8
+	IOERR_SHORT_READ        = IOERR | 2<<8
+	IOERR_WRITE             = IOERR | 3<<8
+	IOERR_FSYNC             = IOERR | 4<<8
+	IOERR_DIR_FSYNC         = IOERR | 5<<8
+	IOERR_TRUNCATE          = IOERR | 6<<8
+	IOERR_FSTAT             = IOERR | 7<<8
+	IOERR_UNLOCK            = IOERR | 8<<8
+	IOERR_RDLOCK            = IOERR | 9<<8
+	IOERR_DELETE            = IOERR | 10<<8
+	IOERR_BLOCKED           = IOERR | 11<<8
+	IOERR_NOMEM             = IOERR | 12<<8
+	IOERR_ACCESS            = IOERR | 13<<8
+	IOERR_CHECKRESERVEDLOCK = IOERR | 14<<8
+	IOERR_LOCK              = IOERR | 15<<8
+	IOERR_CLOSE             = IOERR | 16<<8
+	IOERR_DIR_CLOSE         = IOERR | 17<<8
+	IOERR_SHMOPEN           = IOERR | 18<<8
+	IOERR_SHMSIZE           = IOERR | 19<<8
+	IOERR_SHMLOCK           = IOERR | 20<<8
+	IOERR_SHMMAP            = IOERR | 21<<8
+	IOERR_SEEK              = IOERR | 22<<8
+	IOERR_DELETE_NOENT      = IOERR | 23<<8
+	IOERR_MMAP              = IOERR | 24<<8
+	IOERR_GETTEMPPATH       = IOERR | 25<<8
+	IOERR_CONVPATH          = IOERR | 26<<8
+	IOERR_VNODE             = IOERR | 27<<8
+	IOERR_AUTH              = IOERR | 28<<8
+	IOERR_BEGIN_ATOMIC      = IOERR | 29<<8
+	IOERR_COMMIT_ATOMIC     = IOERR | 30<<8
+	IOERR_ROLLBACK_ATOMIC   = IOERR | 31<<8
+	IOERR_DATA              = IOERR | 32<<8
+	IOERR_CORRUPTFS         = IOERR | 33<<8
+	LOCKED_SHAREDCACHE      = LOCKED | 1<<8
+	LOCKED_VTAB             = LOCKED | 2<<8
+	BUSY_RECOVERY           = BUSY | 1<<8
+	BUSY_SNAPSHOT           = BUSY | 2<<8
+	BUSY_TIMEOUT            = BUSY | 3<<8
+	CANTOPEN_NOTEMPDIR      = CANTOPEN | 1<<8
+	CANTOPEN_ISDIR          = CANTOPEN | 2<<8
+	CANTOPEN_FULLPATH       = CANTOPEN | 3<<8
+	CANTOPEN_CONVPATH       = CANTOPEN | 4<<8
+	CANTOPEN_SYMLINK        = CANTOPEN | 6<<8
+	CORRUPT_VTAB            = CORRUPT | 1<<8
+	CORRUPT_SEQUENCE        = CORRUPT | 2<<8
+	CORRUPT_INDEX           = CORRUPT | 3<<8
+	READONLY_RECOVERY       = READONLY | 1<<8
+	READONLY_CANTLOCK       = READONLY | 2<<8
+	READONLY_ROLLBACK       = READONLY | 3<<8
+	READONLY_DBMOVED        = READONLY | 4<<8
+	READONLY_CANTINIT       = READONLY | 5<<8
+	READONLY_DIRECTORY      = READONLY | 6<<8
+	ABORT_ROLLBACK          = ABORT | 2<<8
+	CONSTRAINT_CHECK        = CONSTRAINT | 1<<8
+	CONSTRAINT_COMMITHOOK   = CONSTRAINT | 2<<8
+	CONSTRAINT_FOREIGNKEY   = CONSTRAINT | 3<<8
+	CONSTRAINT_FUNCTION     = CONSTRAINT | 4<<8
+	CONSTRAINT_NOTNULL      = CONSTRAINT | 5<<8
+	CONSTRAINT_PRIMARYKEY   = CONSTRAINT | 6<<8
+	CONSTRAINT_TRIGGER      = CONSTRAINT | 7<<8
+	CONSTRAINT_UNIQUE       = CONSTRAINT | 8<<8
+	CONSTRAINT_VTAB         = CONSTRAINT | 9<<8
+	CONSTRAINT_ROWID        = CONSTRAINT | 10<<8
+	CONSTRAINT_PINNED       = CONSTRAINT | 11<<8
+	CONSTRAINT_DATATYPE     = CONSTRAINT | 12<<8
+	AUTH_USER               = AUTH | 1<<8
 )
 
 var errText = map[Errno]string{
@@ -77,6 +156,7 @@ var errText = map[Errno]string{
 	DONE:       "sqlite3_step() has finished executing",
 	ENCODER:    "blob encoding failed",
 	SAVEPOINT:  "invalid or unknown savepoint identifier",
+	UNSUPPORTED: "feature not supported by the SQLite3 library",
 }
 
 func SQLiteError(code C.int) (e error) {
@@ -84,4 +164,70 @@ func SQLiteError(code C.int) (e error) {
 		e = nil
 	}
 	return
-}
\ No newline at end of file
+}
+
+// Error describes a failed call into the SQLite engine.
+//
+// Code holds the primary result code and ExtendedCode the more specific
+// extended result code, so that a unique constraint violation matches both
+// errors.Is(e, CONSTRAINT) and errors.Is(e, CONSTRAINT_UNIQUE). Message is
+// the engine's description of the error, SQL the text of the statement
+// involved, if any, and Offset the byte offset into SQL of the token which
+// caused the error, or -1 if unknown.
+type Error struct {
+	Code			Errno
+	ExtendedCode	Errno
+	Message			string
+	SQL				string
+	Offset			int
+}
+
+func (e *Error) Error() (err string) {
+	if err = e.Message; err == "" {
+		err = e.ExtendedCode.Error()
+	}
+	switch {
+	case e.SQL != "" && e.Offset >= 0:
+		err = fmt.Sprintf("%v: %q at offset %v", err, e.SQL, e.Offset)
+	case e.SQL != "":
+		err = fmt.Sprintf("%v: %q", err, e.SQL)
+	}
+	return
+}
+
+// Unwrap returns the primary result code.
+func (e *Error) Unwrap() error {
+	return e.Code
+}
+
+// Is reports whether `target` is the extended result code of the error.
+func (e *Error) Is(target error) bool {
+	return target == e.ExtendedCode
+}
+
+// lastError returns the error for a call into the engine which returned
+// `code`, taking the message and extended result code from the database if
+// they describe the same failure. ROW and DONE are returned as Errno, and
+// constraint violations as *ConstraintError where possible.
+func (db *Database) lastError(code C.int, sql string) error {
+	switch n := Errno(code); {
+	case n == OK:
+		return nil
+	case n == ROW, n == DONE:
+		return n
+	}
+	e := &Error{Code: Errno(code & 0xff), ExtendedCode: Errno(code), SQL: sql, Offset: -1}
+	if db != nil && db.handle != nil {
+		if x := Errno(C.sqlite3_extended_errcode(db.handle)); x & 0xff == e.Code {
+			e.ExtendedCode = x
+			e.Message = C.GoString(C.sqlite3_errmsg(db.handle))
+			if sql != "" {
+				e.Offset = int(C.sqlite3_error_offset(db.handle))
+			}
+		}
+	}
+	if e.Code == CONSTRAINT {
+		return constraintError(e)
+	}
+	return e
+}
@@ -0,0 +1,46 @@
+package sqlite3
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExtendedErrors(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		db.runQuery(t, "CREATE TABLE people (id INTEGER PRIMARY KEY, email TEXT UNIQUE)")
+		db.runQuery(t, "INSERT INTO people VALUES (1, 'a@example.com')")
+		_, e := db.Execute("INSERT INTO people VALUES (2, 'a@example.com')")
+		if !errors.Is(e, CONSTRAINT) || !errors.Is(e, CONSTRAINT_UNIQUE) || errors.Is(e, CONSTRAINT_NOTNULL) {
+			t.Fatalf("expected unique constraint violation, got %v", e)
+		}
+		var err *Error
+		if !errors.As(e, &err) {
+			t.Fatalf("expected *Error, got %T", e)
+		}
+		if err.Code != CONSTRAINT || err.ExtendedCode != CONSTRAINT_UNIQUE || err.Message != "UNIQUE constraint failed: people.email" {
+			t.Fatalf("unexpected error details %+v", err)
+		}
+		if err.SQL != "INSERT INTO people VALUES (2, 'a@example.com')" {
+			t.Fatalf("unexpected SQL %q", err.SQL)
+		}
+
+		_, e = db.Prepare("SELECT * FROM people WHERE nonsense = 1")
+		if !errors.As(e, &err) || err.Code != ERROR || err.Offset != 27 || !strings.Contains(e.Error(), "no such column: nonsense") {
+			t.Fatalf("unexpected prepare error %v", e)
+		}
+	})
+
+	if _, e := Open("/nonexistent/directory/test.db"); !errors.Is(e, CANTOPEN) {
+		t.Fatalf("expected %v, got %v", CANTOPEN, e)
+	}
+}
+
+func TestErrnoText(t *testing.T) {
+	if BUSY_SNAPSHOT.Error() != BUSY.Error() {
+		t.Fatalf("extended code text %q differs from %q", BUSY_SNAPSHOT.Error(), BUSY.Error())
+	}
+	if s := Errno(999).Error(); s != "errno 999" {
+		t.Fatalf("unexpected text %q", s)
+	}
+}
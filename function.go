@@ -0,0 +1,238 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdint.h>
+// #include <stdlib.h>
+// extern void gosqlite3_func(sqlite3_context*, int, sqlite3_value**);
+// extern void gosqlite3_release(void*);
+// static int gosqlite3_create_function(sqlite3* db, const char* name, int nargs, int flags, uintptr_t h) {
+//     return sqlite3_create_function_v2(db, name, nargs, flags, (void*)h, gosqlite3_func, NULL, NULL, gosqlite3_release);
+// }
+// static int gosqlite3_delete_function(sqlite3* db, const char* name, int nargs) {
+//     return sqlite3_create_function_v2(db, name, nargs, SQLITE_UTF8, NULL, NULL, NULL, NULL, NULL);
+// }
+// static void gosqlite3_result_text(sqlite3_context* ctx, const char* s, int n) {
+//     sqlite3_result_text(ctx, s, n, SQLITE_TRANSIENT);
+// }
+// static void gosqlite3_result_blob(sqlite3_context* ctx, const void* b, int n) {
+//     sqlite3_result_blob(ctx, b, n, SQLITE_TRANSIENT);
+// }
+import "C"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// FuncFlag modifies the behaviour of user-defined SQL functions.
+type FuncFlag int
+
+const (
+	F_DETERMINISTIC FuncFlag =	0x000000800
+	F_DIRECTONLY FuncFlag =		0x000080000
+	F_INNOCUOUS FuncFlag =		0x000200000
+)
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	valueType = reflect.TypeOf(Value{})
+)
+
+// function wraps a Go func so that it can be called from SQL.
+type function struct {
+	fn			reflect.Value
+	args		[]reflect.Type
+	variadic	reflect.Type
+	result		bool
+	err			bool
+}
+
+func newFunction(nargs int, fn interface{}) (f *function, e error) {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if t.Kind() != reflect.Func {
+		return nil, errors.New("sqlite3: function must be a func")
+	}
+	f = &function{fn: v}
+	fixed := t.NumIn()
+	if t.IsVariadic() {
+		fixed--
+		f.variadic = t.In(fixed).Elem()
+	}
+	for i := 0; i < fixed; i++ {
+		f.args = append(f.args, t.In(i))
+	}
+	switch {
+	case f.variadic == nil && nargs != fixed:
+		e = fmt.Errorf("sqlite3: function takes %v arguments, not %v", fixed, nargs)
+	case f.variadic != nil && nargs >= 0 && nargs < fixed:
+		e = fmt.Errorf("sqlite3: function takes at least %v arguments, not %v", fixed, nargs)
+	}
+	switch t.NumOut() {
+	case 0:
+	case 1:
+		f.err = t.Out(0) == errorType
+		f.result = !f.err
+	case 2:
+		if t.Out(1) != errorType {
+			e = errors.New("sqlite3: second result of function must be an error")
+		}
+		f.result, f.err = true, true
+	default:
+		e = errors.New("sqlite3: function must return at most a value and an error")
+	}
+	if e != nil {
+		f = nil
+	}
+	return
+}
+
+// call invokes the Go function with the SQL arguments and reports its
+// result or error back to SQLite.
+func (f *function) call(ctx *C.sqlite3_context, argv []*C.sqlite3_value) {
+	defer func() {
+		if x := recover(); x != nil {
+			setResult(ctx, fmt.Errorf("sqlite3: panic in function: %v", x))
+		}
+	}()
+	args, e := f.arguments(argv)
+	if e != nil {
+		setResult(ctx, e)
+		return
+	}
+	out := f.fn.Call(args)
+	switch {
+	case f.err && !out[len(out) - 1].IsNil():
+		setResult(ctx, out[len(out) - 1].Interface())
+	case f.result:
+		setResult(ctx, out[0].Interface())
+	default:
+		setResult(ctx, nil)
+	}
+}
+
+func (f *function) arguments(argv []*C.sqlite3_value) (args []reflect.Value, e error) {
+	if len(argv) < len(f.args) || (f.variadic == nil && len(argv) > len(f.args)) {
+		return nil, fmt.Errorf("sqlite3: wrong number of arguments: %v", len(argv))
+	}
+	args = make([]reflect.Value, len(argv))
+	for i, v := range argv {
+		t := f.variadic
+		if i < len(f.args) {
+			t = f.args[i]
+		}
+		if args[i], e = (Value{v}).convert(t); e != nil {
+			return nil, fmt.Errorf("sqlite3: argument %v: %v", i + 1, e)
+		}
+	}
+	return
+}
+
+// convert returns the value as an instance of the Go type t.
+func (v Value) convert(t reflect.Type) (r reflect.Value, e error) {
+	if t == valueType {
+		return reflect.ValueOf(v), nil
+	}
+	r = reflect.New(t).Elem()
+	if scan := scannerOf(r.Addr().Interface()); scan != nil {
+		return r, scan(v.Interface())
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		if x := v.Interface(); x != nil {
+			if x := reflect.ValueOf(x); x.Type().AssignableTo(t) {
+				r.Set(x)
+			} else {
+				e = MISMATCH
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		r.SetInt(v.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		r.SetUint(uint64(v.Int64()))
+	case reflect.Float32, reflect.Float64:
+		r.SetFloat(v.Float64())
+	case reflect.String:
+		r.SetString(v.Text())
+	case reflect.Bool:
+		r.SetBool(v.Int64() != 0)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			r.SetBytes(v.Bytes())
+		} else {
+			e = MISMATCH
+		}
+	default:
+		e = MISMATCH
+	}
+	return
+}
+
+// setResult reports value as the result of a user-defined function, using
+// the same conversions as QueryParameter.Bind. Errors are reported as SQL
+// errors.
+func setResult(ctx *C.sqlite3_context, value interface{}) {
+	if e, ok := value.(error); ok {
+		cs := C.CString(e.Error())
+		defer C.free(unsafe.Pointer(cs))
+		C.sqlite3_result_error(ctx, cs, -1)
+		return
+	}
+	value, e := sqliteValue(value)
+	if e != nil {
+		setResult(ctx, e)
+		return
+	}
+	switch v := value.(type) {
+	case nil:
+		C.sqlite3_result_null(ctx)
+	case int64:
+		C.sqlite3_result_int64(ctx, C.sqlite3_int64(v))
+	case float64:
+		C.sqlite3_result_double(ctx, C.double(v))
+	case string:
+		cs := C.CString(v)
+		defer C.free(unsafe.Pointer(cs))
+		C.gosqlite3_result_text(ctx, cs, C.int(len(v)))
+	case []byte:
+		setBlobResult(ctx, v)
+	}
+}
+
+func setBlobResult(ctx *C.sqlite3_context, b []byte) {
+	if len(b) == 0 {
+		C.sqlite3_result_zeroblob(ctx, 0)
+	} else {
+		C.gosqlite3_result_blob(ctx, unsafe.Pointer(&b[0]), C.int(len(b)))
+	}
+}
+
+//export gosqlite3_func
+func gosqlite3_func(ctx *C.sqlite3_context, argc C.int, argv **C.sqlite3_value) {
+	f := handleValue(C.sqlite3_user_data(ctx)).(*function)
+	f.call(ctx, unsafe.Slice(argv, int(argc)))
+}
+
+// CreateFunction registers the Go func `fn` as the SQL scalar function
+// `name` taking `nargs` arguments, or any number when `nargs` is -1.
+//
+// Arguments are converted to the types of the parameters of `fn`, which
+// may be any integer, float, string, bool, []byte, Value or interface{}
+// type, and `fn` may be variadic. `fn` may return a value, an error, or
+// both; the value is converted as it would be by QueryParameter.Bind and a
+// non-nil error is raised as an SQL error. Passing a nil `fn` removes a
+// previously registered function.
+func (db *Database) CreateFunction(name string, nargs int, flags FuncFlag, fn interface{}) (e error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	if fn == nil {
+		return SQLiteError(C.gosqlite3_delete_function(db.handle, cname, C.int(nargs)))
+	}
+	var f *function
+	if f, e = newFunction(nargs, fn); e == nil {
+		flags |= C.SQLITE_UTF8
+		e = SQLiteError(C.gosqlite3_create_function(db.handle, cname, C.int(nargs), C.int(flags), newHandle(f)))
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package sqlite3
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func (db *Database) queryValue(t *testing.T, sql string, params... interface{}) (value interface{}) {
+	st, e := db.Prepare(sql, params...)
+	fatalOnError(t, e, "unable to prepare %v", sql)
+	defer st.Finalize()
+	if e = st.Step(); e != ROW {
+		t.Fatalf("%v: expected a row, got %v", sql, e)
+	}
+	return st.Column(0)
+}
+
+func TestCreateFunction(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		fatalOnError(t, db.CreateFunction("sha1", 1, F_DETERMINISTIC, func(b []byte) string {
+			return fmt.Sprintf("%x", sha1.Sum(b))
+		}), "unable to register sha1")
+		fatalOnError(t, db.CreateFunction("implode", -1, F_DETERMINISTIC | F_INNOCUOUS, func(sep string, parts ...string) string {
+			return strings.Join(parts, sep)
+		}), "unable to register implode")
+		fatalOnError(t, db.CreateFunction("positive", 1, F_DIRECTONLY, func(i int64) (bool, error) {
+			if i < 0 {
+				return false, errors.New("negative value")
+			}
+			return i > 0, nil
+		}), "unable to register positive")
+
+		if v := db.queryValue(t, "SELECT sha1('holy moly')"); v != fmt.Sprintf("%x", sha1.Sum([]byte("holy moly"))) {
+			t.Fatalf("sha1 returned %v", v)
+		}
+		if v := db.queryValue(t, "SELECT implode('-', 'a', 'b', ?)", "c"); v != "a-b-c" {
+			t.Fatalf("implode returned %v", v)
+		}
+		if v := db.queryValue(t, "SELECT positive(3)"); v != int64(1) {
+			t.Fatalf("positive returned %v", v)
+		}
+		if _, e := db.Execute("SELECT positive(-3)"); e == nil {
+			t.Fatal("positive(-3) should have failed")
+		}
+
+		fatalOnSuccess(t, db.CreateFunction("bad", 2, 0, func(a int) int { return a }), "registered function with wrong arity")
+		fatalOnError(t, db.CreateFunction("sha1", 1, 0, nil), "unable to remove sha1")
+		if _, e := db.Execute("SELECT sha1('holy moly')"); e == nil {
+			t.Fatal("sha1 still registered after removal")
+		}
+	})
+}
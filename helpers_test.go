@@ -2,7 +2,6 @@ package sqlite3
 
 import (
 	"fmt"
-	"encoding/gob"
 	"testing"
 )
 
@@ -23,6 +22,8 @@ func (t *TwoItems) String() string {
 	return "[" + t.Number + " : " + t.Text + "]"
 }
 
+type Level int8
+
 func fatalOnError(t *testing.T, e error, message string, parameters... interface{}) {
 	if e != nil {
 		t.Fatalf("%v : %v", e, fmt.Sprintf(message, parameters...))
@@ -41,9 +42,9 @@ func (db *Database) stepThroughRows(t *testing.T, table *Table, verbose... bool)
 	c, e = db.Execute(sql, func(st *Statement, values ...interface{}) {
 		data := values[1]
 		switch data := data.(type) {
-		case *gob.Decoder:
+		case []byte:
 			blob := &TwoItems{}
-			data.Decode(blob)
+			fatalOnError(t, GobDecode(data, blob), "unable to decode BLOB")
 			if len(verbose) > 0 && verbose[0] {
 				t.Logf("BLOB =>   %v: %v, %v: %v\n", ResultColumn(0).Name(st), ResultColumn(0).Value(st), st.ColumnName(1), blob)
 			}
@@ -78,7 +79,7 @@ func (db *Database) populate(t *testing.T, table *Table) {
 	case "bar":
 		db.runQuery(t, "INSERT INTO bar values (1, 'this is a test')")
 		db.runQuery(t, "INSERT INTO bar values (?, ?)", 2, "holy moly")
-		db.runQuery(t, "INSERT INTO bar values (?, ?)", 3, TwoItems{ "holy moly", "guacomole" })
+		db.runQuery(t, "INSERT INTO bar values (?, ?)", 3, Gob(TwoItems{ "holy moly", "guacomole" }))
 		if c, _ := table.Rows(db); c != 3 {
 			t.Fatal("Failed to populate %v", table.Name)
 		}
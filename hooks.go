@@ -0,0 +1,132 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdint.h>
+// extern int gosqlite3_commit_hook(uintptr_t);
+// extern void gosqlite3_rollback_hook(uintptr_t);
+// extern void gosqlite3_update_hook(uintptr_t, int, char*, char*, sqlite3_int64);
+// static int gosqlite3_commit(void* p) {
+//     return gosqlite3_commit_hook((uintptr_t)p);
+// }
+// static void gosqlite3_rollback(void* p) {
+//     gosqlite3_rollback_hook((uintptr_t)p);
+// }
+// static void gosqlite3_update(void* p, int op, const char* db, const char* table, sqlite3_int64 rowid) {
+//     gosqlite3_update_hook((uintptr_t)p, op, (char*)db, (char*)table, rowid);
+// }
+// static void gosqlite3_set_commit_hook(sqlite3* db, uintptr_t h) {
+//     sqlite3_commit_hook(db, h ? gosqlite3_commit : NULL, (void*)h);
+// }
+// static void gosqlite3_set_rollback_hook(sqlite3* db, uintptr_t h) {
+//     sqlite3_rollback_hook(db, h ? gosqlite3_rollback : NULL, (void*)h);
+// }
+// static void gosqlite3_set_update_hook(sqlite3* db, uintptr_t h) {
+//     sqlite3_update_hook(db, h ? gosqlite3_update : NULL, (void*)h);
+// }
+import "C"
+import (
+	"runtime/cgo"
+)
+
+// Op identifies the kind of change reported to an update hook.
+type Op int
+
+const (
+	OP_DELETE Op =	9
+	OP_INSERT Op =	18
+	OP_UPDATE Op =	23
+)
+
+var opText = map[Op]string{
+	OP_DELETE:	"DELETE",
+	OP_INSERT:	"INSERT",
+	OP_UPDATE:	"UPDATE",
+}
+
+func (o Op) String() string {
+	return opText[o]
+}
+
+// PreUpdate describes a change about to be made to a table. It is passed
+// to the callback registered with OnPreUpdate and must not be used after
+// the callback returns.
+//
+// OldRowid is the rowid of the row before an UPDATE or DELETE and NewRowid
+// the rowid after an INSERT or UPDATE.
+type PreUpdate struct {
+	db			*Database
+	Op			Op
+	DBName		string
+	Table		string
+	OldRowid	int64
+	NewRowid	int64
+}
+
+// OnCommit registers `f` to be called whenever a transaction is about to
+// be committed. If `f` returns true, or panics, the commit is turned into a
+// rollback. Passing nil removes the hook.
+//
+// `f` must not use the database connection.
+func (db *Database) OnCommit(f func() bool) {
+	var v interface{}
+	if f != nil {
+		v = f
+	}
+	db.setHook("commit", v, func(h C.uintptr_t) {
+		C.gosqlite3_set_commit_hook(db.handle, h)
+	})
+}
+
+// OnRollback registers `f` to be called whenever a transaction is rolled
+// back. Passing nil removes the hook. Panics in `f` are recovered and
+// ignored.
+func (db *Database) OnRollback(f func()) {
+	var v interface{}
+	if f != nil {
+		v = f
+	}
+	db.setHook("rollback", v, func(h C.uintptr_t) {
+		C.gosqlite3_set_rollback_hook(db.handle, h)
+	})
+}
+
+// OnUpdate registers `f` to be called whenever a row is inserted, updated
+// or deleted in a rowid table. Passing nil removes the hook. Panics in `f`
+// are recovered and ignored.
+//
+// `f` must not use the database connection.
+func (db *Database) OnUpdate(f func(op Op, dbName, table string, rowid int64)) {
+	var v interface{}
+	if f != nil {
+		v = f
+	}
+	db.setHook("update", v, func(h C.uintptr_t) {
+		C.gosqlite3_set_update_hook(db.handle, h)
+	})
+}
+
+//export gosqlite3_commit_hook
+func gosqlite3_commit_hook(h C.uintptr_t) (r C.int) {
+	defer func() {
+		if recover() != nil {
+			r = 1
+		}
+	}()
+	if cgo.Handle(h).Value().(func() bool)() {
+		return 1
+	}
+	return 0
+}
+
+//export gosqlite3_rollback_hook
+func gosqlite3_rollback_hook(h C.uintptr_t) {
+	defer func() { recover() }()
+	cgo.Handle(h).Value().(func())()
+}
+
+//export gosqlite3_update_hook
+func gosqlite3_update_hook(h C.uintptr_t, op C.int, db, table *C.char, rowid C.sqlite3_int64) {
+	defer func() { recover() }()
+	f := cgo.Handle(h).Value().(func(Op, string, string, int64))
+	f(Op(op), C.GoString(db), C.GoString(table), int64(rowid))
+}
@@ -0,0 +1,102 @@
+package sqlite3
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		FOO.Create(db)
+
+		changes := []string{}
+		db.OnUpdate(func(op Op, dbName, table string, rowid int64) {
+			changes = append(changes, fmt.Sprintf("%v %v.%v %v", op, dbName, table, rowid))
+		})
+		commits, rollbacks := 0, 0
+		veto := false
+		db.OnCommit(func() bool {
+			commits++
+			return veto
+		})
+		db.OnRollback(func() {
+			rollbacks++
+		})
+
+		db.runQuery(t, "INSERT INTO foo VALUES (1, 'holy moly')")
+		db.runQuery(t, "UPDATE foo SET text = 'guacomole' WHERE number = 1")
+		db.runQuery(t, "DELETE FROM foo WHERE number = 1")
+		if len(changes) != 3 || changes[0] != "INSERT main.foo 1" || changes[1] != "UPDATE main.foo 1" || changes[2] != "DELETE main.foo 1" {
+			t.Fatalf("unexpected changes %v", changes)
+		}
+		if commits != 3 {
+			t.Fatalf("expected 3 commits, got %v", commits)
+		}
+
+		veto = true
+		fatalOnError(t, db.Begin(), "unable to begin transaction")
+		db.runQuery(t, "INSERT INTO foo VALUES (2, 'vetoed')")
+		fatalOnSuccess(t, db.Commit(), "vetoed commit succeeded")
+		if rollbacks != 1 {
+			t.Fatalf("expected 1 rollback, got %v", rollbacks)
+		}
+		if c, _ := FOO.Rows(db); c != 0 {
+			t.Fatalf("vetoed insert left %v rows", c)
+		}
+
+		db.OnCommit(nil)
+		db.OnRollback(nil)
+		db.OnUpdate(nil)
+		db.runQuery(t, "INSERT INTO foo VALUES (3, 'unhooked')")
+		if len(changes) != 4 || rollbacks != 1 {
+			t.Fatalf("hooks still called after removal: %v, %v", changes, rollbacks)
+		}
+	})
+}
+
+func TestHookPanics(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		FOO.Create(db)
+		db.OnUpdate(func(Op, string, string, int64) { panic("update") })
+		db.OnRollback(func() { panic("rollback") })
+		db.OnCommit(func() bool { panic("commit") })
+		_, e := db.Execute("INSERT INTO foo VALUES (1, 'panicked')")
+		fatalOnSuccess(t, e, "commit succeeded despite panic")
+		if c, _ := FOO.Rows(db); c != 0 {
+			t.Fatalf("panicking commit hook left %v rows", c)
+		}
+		db.OnCommit(nil)
+		db.runQuery(t, "INSERT INTO foo VALUES (2, 'recovered')")
+		if c, _ := FOO.Rows(db); c != 1 {
+			t.Fatalf("expected 1 row, got %v", c)
+		}
+	})
+}
+
+func TestPreUpdateHook(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		FOO.Create(db)
+		db.runQuery(t, "INSERT INTO foo VALUES (1, 'holy moly')")
+
+		changes := []string{}
+		if e := db.OnPreUpdate(func(p *PreUpdate) {
+			before, _ := p.Old()
+			after, _ := p.New()
+			changes = append(changes, fmt.Sprintf("%v %v %v->%v %v %v %v", p.Op, p.Table, p.OldRowid, p.NewRowid, before, after, p.Depth()))
+		}); e == UNSUPPORTED {
+			t.Skip("preupdate hook not supported")
+		}
+		db.runQuery(t, "UPDATE foo SET text = 'guacomole' WHERE number = 1")
+		db.runQuery(t, "INSERT INTO foo VALUES (2, 'new')")
+		db.runQuery(t, "DELETE FROM foo WHERE number = 2")
+		expected := []string{
+			"UPDATE foo 1->1 [1 holy moly] [1 guacomole] 0",
+			"INSERT foo 2->2 [] [2 new] 0",
+			"DELETE foo 2->2 [2 new] [] 0",
+		}
+		if fmt.Sprint(changes) != fmt.Sprint(expected) {
+			t.Fatalf("unexpected changes %v", changes)
+		}
+		fatalOnError(t, db.OnPreUpdate(nil), "unable to remove preupdate hook")
+	})
+}
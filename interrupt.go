@@ -0,0 +1,146 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdint.h>
+// extern int gosqlite3_progress_hook(uintptr_t);
+// static int gosqlite3_progress(void* p) {
+//     return gosqlite3_progress_hook((uintptr_t)p);
+// }
+// static void gosqlite3_set_progress_handler(sqlite3* db, int n, uintptr_t h) {
+//     sqlite3_progress_handler(db, n, h ? gosqlite3_progress : NULL, (void*)h);
+// }
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/cgo"
+)
+
+// Interrupt causes any pending operation on the database to abort with
+// INTERRUPT at its earliest opportunity.
+func (db *Database) Interrupt() {
+	C.sqlite3_interrupt(db.handle)
+}
+
+// SetProgressHandler registers `f` to be called periodically, roughly every
+// `n` virtual machine instructions, while statements are evaluated. If `f`
+// returns true, or panics, the operation is aborted with INTERRUPT. Passing
+// a nil `f` or an `n` less than 1 removes the handler.
+func (db *Database) SetProgressHandler(n int, f func() bool) {
+	var v interface{}
+	if f != nil && n > 0 {
+		v = f
+	}
+	db.setHook("progress", v, func(h C.uintptr_t) {
+		C.gosqlite3_set_progress_handler(db.handle, C.int(n), h)
+	})
+}
+
+//export gosqlite3_progress_hook
+func gosqlite3_progress_hook(h C.uintptr_t) (r C.int) {
+	defer func() {
+		if recover() != nil {
+			r = 1
+		}
+	}()
+	if cgo.Handle(h).Value().(func() bool)() {
+		return 1
+	}
+	return 0
+}
+
+// interruptOn arranges for the database to be interrupted when `ctx` is
+// done. The returned function must be called once the operation has
+// finished and waits for any interrupt in progress to complete, so that a
+// late interrupt can not abort later statements.
+func (db *Database) interruptOn(ctx context.Context) (release func()) {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	done := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		db.Interrupt()
+		close(done)
+	})
+	return func() {
+		if !stop() {
+			<-done
+		}
+	}
+}
+
+// contextError wraps an INTERRUPT caused by `ctx` being done so that both
+// INTERRUPT and the context error can be matched with errors.Is.
+func contextError(ctx context.Context, e error) error {
+	switch {
+	case ctx.Err() == nil:
+	case e == nil:
+		return fmt.Errorf("%w: %w", INTERRUPT, ctx.Err())
+	case errors.Is(e, INTERRUPT):
+		return fmt.Errorf("%w: %w", e, ctx.Err())
+	}
+	return e
+}
+
+// PrepareContext is like Prepare but fails if `ctx` is already done.
+func (db *Database) PrepareContext(ctx context.Context, sql string, values ...interface{}) (s *Statement, e error) {
+	if ctx.Err() != nil {
+		return nil, contextError(ctx, nil)
+	}
+	return db.Prepare(sql, values...)
+}
+
+// ExecuteContext is like Execute but interrupts the statement when `ctx` is
+// done, returning an error matching both INTERRUPT and ctx.Err().
+func (db *Database) ExecuteContext(ctx context.Context, sql string, f ...func(*Statement, ...interface{})) (c int, e error) {
+	if ctx.Err() != nil {
+		return 0, contextError(ctx, nil)
+	}
+	var st *Statement
+	if st, e = db.prepareCached(sql); e == nil {
+		c, e = st.AllContext(ctx, f...)
+	}
+	return
+}
+
+// StepContext is like Step but interrupts the statement when `ctx` is done,
+// returning an error matching both INTERRUPT and ctx.Err().
+//
+// Interrupting affects every statement running on the database at the
+// time, not just this one.
+func (s *Statement) StepContext(ctx context.Context, f ...func(*Statement, ...interface{})) (e error) {
+	if ctx.Err() != nil {
+		return contextError(ctx, nil)
+	}
+	release := s.db.interruptOn(ctx)
+	e = s.Step(f...)
+	release()
+	if errors.Is(e, INTERRUPT) {
+		e = contextError(ctx, e)
+	}
+	return
+}
+
+// AllContext is like All but interrupts the statement when `ctx` is done,
+// returning an error matching both INTERRUPT and ctx.Err(). The statement
+// is finalized in either case.
+func (s *Statement) AllContext(ctx context.Context, f ...func(*Statement, ...interface{})) (c int, e error) {
+	if ctx.Err() != nil {
+		s.Finalize()
+		return 0, contextError(ctx, nil)
+	}
+	release := s.db.interruptOn(ctx)
+	for e = s.Step(f...); e == ROW; e = s.Step(f...) {
+		c++
+	}
+	release()
+	if errors.Is(e, INTERRUPT) {
+		s.Finalize()
+		return c, contextError(ctx, e)
+	}
+	if err := s.Finalize(); e == nil {
+		e = err
+	}
+	return
+}
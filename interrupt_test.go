@@ -0,0 +1,77 @@
+package sqlite3
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const endlessQuery = "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT COUNT(*) FROM c"
+
+func TestExecuteContext(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20 * time.Millisecond)
+		defer cancel()
+		_, e := db.ExecuteContext(ctx, endlessQuery)
+		if !errors.Is(e, INTERRUPT) || !errors.Is(e, context.DeadlineExceeded) {
+			t.Fatalf("expected interrupted query, got %v", e)
+		}
+
+		_, e = db.PrepareContext(ctx, "SELECT 1")
+		if !errors.Is(e, context.DeadlineExceeded) {
+			t.Fatalf("prepared statement with expired context: %v", e)
+		}
+
+		st, e := db.PrepareContext(context.Background(), "SELECT 1")
+		fatalOnError(t, e, "unable to prepare statement after interrupt")
+		if e = st.StepContext(context.Background()); e != ROW {
+			t.Fatalf("statement after interrupt returned %v", e)
+		}
+		st.Finalize()
+
+		ctx, cancel = context.WithCancel(context.Background())
+		st, _ = db.Prepare(endlessQuery)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+		if e = st.StepContext(ctx); !errors.Is(e, context.Canceled) {
+			t.Fatalf("expected cancelled step, got %v", e)
+		}
+		st.Finalize()
+	})
+}
+
+func TestProgressHandler(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		calls := 0
+		db.SetProgressHandler(1000, func() bool {
+			calls++
+			return calls == 10
+		})
+		if _, e := db.Execute(endlessQuery); !errors.Is(e, INTERRUPT) {
+			t.Fatalf("expected %v, got %v", INTERRUPT, e)
+		}
+		db.SetProgressHandler(1000, func() bool { panic("progress") })
+		if _, e := db.Execute(endlessQuery); !errors.Is(e, INTERRUPT) {
+			t.Fatalf("expected %v, got %v", INTERRUPT, e)
+		}
+		db.SetProgressHandler(0, nil)
+		if v := db.queryValue(t, "SELECT COUNT(*) FROM (WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c LIMIT 10000) SELECT x FROM c)"); v != int64(10000) || calls != 10 {
+			t.Fatalf("progress handler still active: %v, %v", v, calls)
+		}
+	})
+}
+
+func TestDriverContext(t *testing.T) {
+	db := openTestDriver(t)
+	defer db.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 20 * time.Millisecond)
+	defer cancel()
+	var c int64
+	if e := db.QueryRowContext(ctx, endlessQuery).Scan(&c); e == nil {
+		t.Fatal("endless query was not cancelled")
+	}
+	fatalOnError(t, db.QueryRow("SELECT 1").Scan(&c), "connection unusable after cancellation")
+}
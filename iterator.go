@@ -0,0 +1,90 @@
+package sqlite3
+
+import "iter"
+
+// Row gives access to the current row of a statement while iterating over
+// Statement.Rows. It is only valid until the next iteration.
+type Row struct {
+	s			*Statement
+}
+
+// Columns returns the number of columns in the row.
+func (r *Row) Columns() int {
+	return r.s.Columns()
+}
+
+// ColumnName returns the name of the column.
+func (r *Row) ColumnName(column int) string {
+	return r.s.ColumnName(column)
+}
+
+// Column returns the value of the column.
+func (r *Row) Column(column int) interface{} {
+	return r.s.Column(column)
+}
+
+// Values returns all values of the row.
+func (r *Row) Values() []interface{} {
+	return r.s.Row()
+}
+
+// Scan copies the columns of the row into `dest`, as for Rows.Scan.
+func (r *Row) Scan(dest ...interface{}) error {
+	return scanRow(r.s, dest)
+}
+
+// Rows returns an iterator over the rows produced by the statement. An
+// error ends the iteration after being yielded with a nil Row. The
+// statement is reset once the iteration ends, including when the loop is
+// left early, so that it can be ranged over again, but it remains the
+// caller's to finalize.
+//
+//	for row, e := range st.Rows() {
+//		if e != nil {
+//			...
+//		}
+//		...
+//	}
+func (s *Statement) Rows() iter.Seq2[*Row, error] {
+	return func(yield func(*Row, error) bool) {
+		defer s.Reset()
+		row := &Row{s: s}
+		for {
+			switch e := s.Step(); e {
+			case ROW:
+				if !yield(row, nil) {
+					return
+				}
+			case nil:
+				return
+			default:
+				yield(nil, e)
+				return
+			}
+		}
+	}
+}
+
+// QueryAll runs `sql` with `values` bound to its parameters and returns an
+// iterator over its rows. Rows are scanned into a T by ScanStruct if T is a
+// struct, and as a single column by Rows.Scan otherwise.
+func QueryAll[T any](db *Database, sql string, values ...interface{}) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var v T
+		s, e := db.prepareCached(sql, values...)
+		if e != nil {
+			yield(v, e)
+			return
+		}
+		defer s.Finalize()
+		for row, e := range s.Rows() {
+			if e == nil {
+				v = *new(T)
+				e = row.s.scanInto(&v)
+			}
+			if !yield(v, e) || e != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package sqlite3
+
+import "testing"
+
+func TestStatementRows(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		FOO.Create(db)
+		db.populate(t, FOO)
+		st, e := db.Prepare("SELECT number, text FROM foo ORDER BY number")
+		fatalOnError(t, e, "unable to prepare")
+		var numbers []int64
+		for row, e := range st.Rows() {
+			fatalOnError(t, e, "iteration failed")
+			var n int64
+			var s string
+			fatalOnError(t, row.Scan(&n, &s), "unable to scan")
+			if row.Columns() != 2 || row.ColumnName(1) != "text" || row.Values()[1] != s {
+				t.Fatalf("unexpected row %v", row.Values())
+			}
+			numbers = append(numbers, n)
+		}
+		if len(numbers) != 2 || numbers[0] != 1 || numbers[1] != 2 {
+			t.Fatalf("unexpected rows %v", numbers)
+		}
+
+		for range st.Rows() {
+			break
+		}
+		count := 0
+		for _, e := range st.Rows() {
+			fatalOnError(t, e, "second iteration failed")
+			count++
+		}
+		if count != 2 {
+			t.Fatalf("expected 2 rows when ranging again, got %v", count)
+		}
+		fatalOnError(t, st.Finalize(), "unable to finalize after ranging")
+		fatalOnError(t, st.Finalize(), "second Finalize failed")
+
+		db.SetStatementCacheSize(1)
+		for n, e := range QueryAll[int](db, "SELECT number FROM foo WHERE number > ? ORDER BY number", 0) {
+			fatalOnError(t, e, "iteration failed")
+			if n != 1 {
+				t.Fatalf("expected 1, got %v", n)
+			}
+			break
+		}
+		if s := db.StatementCacheStats(); s.Cached != 1 {
+			t.Fatalf("statement not finalized after break: %+v", s)
+		}
+
+		var texts []*string
+		for s, e := range QueryAll[*string](db, "SELECT text FROM foo UNION ALL SELECT NULL") {
+			fatalOnError(t, e, "iteration failed")
+			texts = append(texts, s)
+		}
+		if len(texts) != 3 || *texts[0] != "this is a test" || texts[2] != nil {
+			t.Fatalf("unexpected values %v", texts)
+		}
+
+		count = 0
+		for _, e := range QueryAll[int](db, "SELECT * FROM missing") {
+			if e == nil {
+				t.Fatal("query on missing table succeeded")
+			}
+			count++
+		}
+		for _, e := range QueryAll[int](db, "SELECT number, text FROM foo") {
+			if e == nil {
+				t.Fatal("scanned two columns into an int")
+			}
+			count++
+		}
+		if count != 2 {
+			t.Fatalf("expected each error to end iteration, got %v errors", count)
+		}
+	})
+}
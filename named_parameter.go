@@ -0,0 +1,165 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// ParameterName returns the name of the SQL parameter at `index`, including
+// its ":", "@", "$" or "?" prefix. Anonymous "?" parameters have no name.
+func (s *Statement) ParameterName(index int) string {
+	return C.GoString(C.sqlite3_bind_parameter_name(s.cptr, C.int(index)))
+}
+
+// ParameterIndex returns the index of the SQL parameter called `name`, or 0
+// if there is none. The prefix may be left out of `name`, in which case
+// the ":", "@" and "$" forms are tried in turn.
+func (s *Statement) ParameterIndex(name string) int {
+	names := []string{name}
+	if name != "" && !strings.ContainsRune(":@$?", rune(name[0])) {
+		names = []string{":" + name, "@" + name, "$" + name}
+	}
+	for _, n := range names {
+		cs := C.CString(n)
+		i := C.sqlite3_bind_parameter_index(s.cptr, cs)
+		C.free(unsafe.Pointer(cs))
+		if i > 0 {
+			return int(i)
+		}
+	}
+	return 0
+}
+
+// namedParameters returns the index of each :VVV, @VVV and $VVV parameter
+// of the statement keyed by its name including prefix.
+func (s *Statement) namedParameters() (p map[string]int) {
+	p = make(map[string]int)
+	for i := 1; i <= s.Parameters(); i++ {
+		if name := s.ParameterName(i); name != "" && name[0] != '?' {
+			p[name] = i
+		}
+	}
+	return
+}
+
+func bindingError(unbound, unknown, ambiguous []string) error {
+	var problems []string
+	for _, p := range []struct {
+		text	string
+		names	[]string
+	}{
+		{"unbound parameters", unbound},
+		{"unknown parameters", unknown},
+		{"ambiguous parameters", ambiguous},
+	} {
+		if len(p.names) > 0 {
+			sort.Strings(p.names)
+			problems = append(problems, p.text + " " + strings.Join(p.names, ", "))
+		}
+	}
+	return fmt.Errorf("sqlite3: %v", strings.Join(problems, "; "))
+}
+
+// BindNamed binds the :VVV, @VVV and $VVV parameters of the statement to
+// the entries of `values`. A key with a prefix binds only the parameter of
+// that name, while a key without one binds each of its :VVV, @VVV and $VVV
+// forms present in the statement. Nothing is bound if a named parameter has
+// no entry, an entry matches no parameter, or several entries match the same
+// parameter, and the error lists the names involved. Positional parameters
+// are left unchanged.
+func (s *Statement) BindNamed(values map[string]interface{}) (e error) {
+	params := s.namedParameters()
+	var unbound, unknown, ambiguous []string
+	bound := make(map[string]interface{})
+	for key, v := range values {
+		names := []string{key}
+		if key == "" || !strings.ContainsRune(":@$", rune(key[0])) {
+			names = []string{":" + key, "@" + key, "$" + key}
+		}
+		matched := false
+		for _, name := range names {
+			if _, ok := params[name]; !ok {
+				continue
+			}
+			matched = true
+			if _, ok := bound[name]; ok {
+				ambiguous = append(ambiguous, name)
+			}
+			bound[name] = v
+		}
+		if !matched {
+			unknown = append(unknown, key)
+		}
+	}
+	for name := range params {
+		if _, ok := bound[name]; !ok {
+			unbound = append(unbound, name)
+		}
+	}
+	if len(unbound) > 0 || len(unknown) > 0 || len(ambiguous) > 0 {
+		return bindingError(unbound, unknown, ambiguous)
+	}
+	for name, i := range params {
+		if e = QueryParameter(i).Bind(s, bound[name]); e != nil {
+			return
+		}
+	}
+	return
+}
+
+// BindStruct binds the :VVV, @VVV and $VVV parameters of the statement to
+// the fields of the struct, or pointer to struct, `value`. Parameters are
+// matched to fields as columns are by ScanStruct, and nil pointer fields are
+// bound as NULL. Nothing is bound if a parameter has no matching field, and
+// the error lists the names involved.
+func (s *Statement) BindStruct(value interface{}) (e error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() || !isStruct(v.Type()) {
+		return fmt.Errorf("sqlite3: BindStruct value %T is not a struct", value)
+	}
+	fields := fieldsOf(v.Type())
+	params := s.namedParameters()
+	var unbound []string
+	for name := range params {
+		if _, ok := fields[strings.ToLower(name[1:])]; !ok {
+			unbound = append(unbound, name)
+		}
+	}
+	if len(unbound) > 0 {
+		return bindingError(unbound, nil, nil)
+	}
+	for name, i := range params {
+		var x interface{}
+		if f, ok := fieldValue(v, fields[strings.ToLower(name[1:])]); ok {
+			x = f.Interface()
+		}
+		if e = QueryParameter(i).Bind(s, x); e != nil {
+			return
+		}
+	}
+	return
+}
+
+// fieldValue returns the field at `index`, and false if a nil embedded
+// struct pointer is found on the way.
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, x := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -0,0 +1,107 @@
+package sqlite3
+
+import "testing"
+
+func TestParameterNames(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		st, e := db.Prepare("SELECT :a, @b, $c, ?, ?9")
+		fatalOnError(t, e, "unable to prepare")
+		defer st.Finalize()
+		for i, name := range []string{"", ":a", "@b", "$c", "", "", "", "", "", "?9"} {
+			if n := st.ParameterName(i); n != name {
+				t.Fatalf("parameter %v: expected %q, got %q", i, name, n)
+			}
+		}
+		for name, i := range map[string]int{"a": 1, ":a": 1, "b": 2, "@b": 2, "$c": 3, "?9": 9, "d": 0, "$a": 0} {
+			if n := st.ParameterIndex(name); n != i {
+				t.Fatalf("parameter %v: expected %v, got %v", name, i, n)
+			}
+		}
+	})
+}
+
+func TestBindNamed(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		st, e := db.Prepare("SELECT :name || ' ' || @surname, $age + ?")
+		fatalOnError(t, e, "unable to prepare")
+		defer st.Finalize()
+		e = st.BindNamed(map[string]interface{}{"name": "Ada", "rank": 1})
+		if e == nil || e.Error() != "sqlite3: unbound parameters $age, @surname; unknown parameters rank" {
+			t.Fatalf("unexpected error %v", e)
+		}
+		fatalOnError(t, st.BindNamed(map[string]interface{}{":name": "Ada", "surname": "Lovelace", "$age": 36}), "unable to bind")
+		e, _ = st.Bind(3, 1)
+		fatalOnError(t, e, "unable to bind positional parameter")
+		if e = st.Step(); e != ROW || st.Column(0) != "Ada Lovelace" || st.Column(1) != int64(37) {
+			t.Fatalf("unexpected result %v: %v", e, st.Row())
+		}
+
+		st.Reset()
+		fatalOnSuccess(t, st.BindNamed(map[string]interface{}{"$name": "Grace", ":surname": "Hopper", "@age": 85}), "bound with other prefixes")
+		fatalOnError(t, st.BindNamed(map[string]interface{}{"name": "Grace", "surname": "Hopper", "age": 85}), "unable to bind")
+		if e = st.Step(); e != ROW || st.Column(0) != "Grace Hopper" || st.Column(1) != int64(86) {
+			t.Fatalf("unexpected result %v: %v", e, st.Row())
+		}
+	})
+}
+
+func TestBindNamedPrefixes(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		st, e := db.Prepare("SELECT :x, $x, @y")
+		fatalOnError(t, e, "unable to prepare")
+		defer st.Finalize()
+		for _, c := range []struct {
+			values		map[string]interface{}
+			message		string
+		}{
+			{map[string]interface{}{":x": 1, "y": 3}, "sqlite3: unbound parameters $x"},
+			{map[string]interface{}{"x": 1, ":x": 2, "@y": 3}, "sqlite3: ambiguous parameters :x"},
+			{map[string]interface{}{"x": 1, "y": 3, "@x": 4}, "sqlite3: unknown parameters @x"},
+		} {
+			if e = st.BindNamed(c.values); e == nil || e.Error() != c.message {
+				t.Fatalf("%v: unexpected error %v", c.values, e)
+			}
+		}
+		fatalOnError(t, st.BindNamed(map[string]interface{}{"x": 1, "y": 3}), "unable to bind bare names")
+		if e = st.Step(); e != ROW || st.Column(0) != int64(1) || st.Column(1) != int64(1) || st.Column(2) != int64(3) {
+			t.Fatalf("unexpected result %v: %v", e, st.Row())
+		}
+		st.Reset()
+		fatalOnError(t, st.BindNamed(map[string]interface{}{":x": 1, "$x": 2, "y": 3}), "unable to bind prefixed names")
+		if e = st.Step(); e != ROW || st.Column(0) != int64(1) || st.Column(1) != int64(2) {
+			t.Fatalf("unexpected result %v: %v", e, st.Row())
+		}
+	})
+}
+
+func TestBindStruct(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		createPeople(t, db)
+		st, e := db.Prepare("INSERT INTO people (id, name, email) VALUES (:id, :name, :email)")
+		fatalOnError(t, e, "unable to prepare")
+		fatalOnError(t, st.BindStruct(&Person{ID: 3, Name: "Grace"}), "unable to bind")
+		fatalOnError(t, st.Step(), "unable to insert")
+		email := "kurt@example.com"
+		fatalOnError(t, st.BindStruct(Person{ID: 4, Name: "Kurt", Contact: &Contact{Email: &email}}), "unable to bind")
+		fatalOnError(t, st.Step(), "unable to insert")
+		st.Finalize()
+
+		var p Person
+		fatalOnError(t, db.Get(&p, "SELECT id, name, email FROM people WHERE id = 3"), "unable to get")
+		if p.Name != "Grace" || p.Email != nil {
+			t.Fatalf("unexpected person %+v", p)
+		}
+		fatalOnError(t, db.Get(&p, "SELECT id, name, email FROM people WHERE id = 4"), "unable to get")
+		if p.Email == nil || *p.Email != email {
+			t.Fatalf("unexpected person %+v", p)
+		}
+
+		st, _ = db.Prepare("SELECT :name, :age")
+		defer st.Finalize()
+		if e = st.BindStruct(p); e == nil || e.Error() != "sqlite3: unbound parameters :age" {
+			t.Fatalf("unexpected error %v", e)
+		}
+		fatalOnSuccess(t, st.BindStruct(nil), "bound nil")
+		fatalOnSuccess(t, st.BindStruct((*Person)(nil)), "bound nil pointer")
+	})
+}
@@ -0,0 +1,254 @@
+package sqlite3
+
+// #include <sqlite3.h>
+import "C"
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PoolConfig configures a Pool.
+//
+// Readers is the maximum number of read-only connections open at once, and
+// MaxIdle how many of them are kept open while unused. Connections older
+// than MaxLifetime are closed instead of being reused; a zero MaxLifetime
+// keeps them indefinitely. Init, if set, is run on every new connection
+// before it is first handed out.
+type PoolConfig struct {
+	Readers			int
+	MaxIdle			int
+	MaxLifetime		time.Duration
+	Init			func(db *Database) error
+}
+
+// Pool manages one writer and several reader connections to a database in
+// WAL mode, which allows the readers to run concurrently with the writer.
+//
+// Each connection is used by a single caller at a time, from Acquire until
+// the matching Release.
+type Pool struct {
+	Filename	string
+	PoolConfig
+	writer		*connSet
+	readers		*connSet
+	sync.Mutex
+	inUse		map[*Database]*pooledConn
+	routes		map[string]bool
+	closed		bool
+}
+
+type pooledConn struct {
+	db			*Database
+	set			*connSet
+	opened		time.Time
+}
+
+// connSet holds the idle connections of one kind together with a
+// semaphore limiting how many may be open.
+type connSet struct {
+	flags		[]DBFlag
+	maxIdle		int
+	slots		chan struct{}
+	idle		[]*pooledConn
+}
+
+// ErrPoolClosed is returned when acquiring a connection from a closed Pool.
+var ErrPoolClosed = errors.New("sqlite3: pool is closed")
+
+// ErrPoolTransaction is returned by Pool.Execute for statements which begin
+// or end a transaction, as the connection is released as soon as the
+// statement has run.
+var ErrPoolTransaction = errors.New("sqlite3: transactions must be run on a connection obtained from Acquire")
+
+// maxRoutes bounds the number of statements whose routing a Pool remembers.
+const maxRoutes = 1024
+
+// NewPool opens the writer connection to `filename`, switching the database
+// to WAL mode, and returns a Pool handing out connections to it.
+func NewPool(filename string, config PoolConfig) (p *Pool, e error) {
+	if config.Readers < 1 {
+		config.Readers = 1
+	}
+	p = &Pool{
+		Filename:	filename,
+		PoolConfig:	config,
+		writer:		&connSet{flags: []DBFlag{O_FULLMUTEX, O_READWRITE, O_CREATE}, maxIdle: 1, slots: make(chan struct{}, 1)},
+		readers:	&connSet{flags: []DBFlag{O_FULLMUTEX, O_READONLY}, maxIdle: config.MaxIdle, slots: make(chan struct{}, config.Readers)},
+		inUse:		make(map[*Database]*pooledConn),
+		routes:		make(map[string]bool),
+	}
+	var db *Database
+	if db, e = p.Acquire(context.Background(), true); e != nil {
+		return nil, e
+	}
+	_, e = db.Execute("PRAGMA journal_mode = WAL")
+	p.Release(db)
+	if e != nil {
+		p.Close()
+		p = nil
+	}
+	return
+}
+
+// Acquire returns the writer connection if `write` is true, or a reader
+// connection otherwise, waiting until one is available or `ctx` is done.
+func (p *Pool) Acquire(ctx context.Context, write bool) (db *Database, e error) {
+	set := p.readers
+	if write {
+		set = p.writer
+	}
+	select {
+	case set.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var c *pooledConn
+	p.Lock()
+	if p.closed {
+		p.Unlock()
+		<-set.slots
+		return nil, ErrPoolClosed
+	}
+	for c == nil && len(set.idle) > 0 {
+		c, set.idle = set.idle[len(set.idle) - 1], set.idle[:len(set.idle) - 1]
+		if p.expired(c) {
+			c.db.Close()
+			c = nil
+		}
+	}
+	p.Unlock()
+	if c == nil {
+		if c, e = p.open(set); e != nil {
+			<-set.slots
+			return nil, e
+		}
+	}
+	p.Lock()
+	p.inUse[c.db] = c
+	p.Unlock()
+	return c.db, nil
+}
+
+func (p *Pool) open(set *connSet) (c *pooledConn, e error) {
+	var db *Database
+	if db, e = Open(p.Filename, set.flags...); e != nil {
+		return
+	}
+	if p.Init != nil {
+		if e = p.Init(db); e != nil {
+			db.Close()
+			return
+		}
+	}
+	return &pooledConn{db: db, set: set, opened: time.Now()}, nil
+}
+
+func (p *Pool) expired(c *pooledConn) bool {
+	return p.MaxLifetime > 0 && time.Since(c.opened) > p.MaxLifetime
+}
+
+// Release returns a connection obtained from Acquire to the pool. Any
+// transaction left open on it is rolled back.
+func (p *Pool) Release(db *Database) {
+	p.Lock()
+	c, ok := p.inUse[db]
+	delete(p.inUse, db)
+	p.Unlock()
+	if !ok {
+		return
+	}
+	if C.sqlite3_get_autocommit(db.handle) == 0 {
+		db.Rollback()
+	}
+	p.Lock()
+	if p.closed || p.expired(c) || len(c.set.idle) >= c.set.maxIdle {
+		db.Close()
+	} else {
+		c.set.idle = append(c.set.idle, c)
+	}
+	p.Unlock()
+	<-c.set.slots
+}
+
+// Close closes all idle connections and stops handing out new ones.
+// Connections in use are closed when released.
+func (p *Pool) Close() {
+	p.Lock()
+	defer p.Unlock()
+	p.closed = true
+	for _, set := range []*connSet{p.writer, p.readers} {
+		for _, c := range set.idle {
+			c.db.Close()
+		}
+		set.idle = nil
+	}
+}
+
+// Execute is like ExecuteContext without a deadline.
+func (p *Pool) Execute(sql string, f ...func(*Statement, ...interface{})) (c int, e error) {
+	return p.ExecuteContext(context.Background(), sql, f...)
+}
+
+// ExecuteContext runs the SQL statement on a reader connection if it makes
+// no changes to the database, and on the writer connection otherwise. The
+// choice is remembered, so a statement is only prepared on a reader to find
+// out the first time it is seen.
+//
+// Each call may use a different connection, so transactions must be run on
+// a connection obtained from Acquire, and statements such as BEGIN, COMMIT
+// and SAVEPOINT fail with ErrPoolTransaction.
+func (p *Pool) ExecuteContext(ctx context.Context, sql string, f ...func(*Statement, ...interface{})) (c int, e error) {
+	if transactionControl(sql) {
+		return 0, ErrPoolTransaction
+	}
+	p.Lock()
+	write, known := p.routes[sql]
+	p.Unlock()
+	var db *Database
+	if !known {
+		if db, e = p.Acquire(ctx, false); e != nil {
+			return
+		}
+		var st *Statement
+		if st, e = db.prepareCached(sql); e != nil {
+			p.Release(db)
+			return
+		}
+		write = !st.ReadOnly()
+		p.Lock()
+		if len(p.routes) >= maxRoutes {
+			clear(p.routes)
+		}
+		p.routes[sql] = write
+		p.Unlock()
+		if !write {
+			c, e = st.AllContext(ctx, f...)
+			p.Release(db)
+			return
+		}
+		st.Finalize()
+		p.Release(db)
+	}
+	if db, e = p.Acquire(ctx, write); e == nil {
+		c, e = db.ExecuteContext(ctx, sql, f...)
+		p.Release(db)
+	}
+	return
+}
+
+// transactionControl reports whether `sql` begins with a statement which
+// starts or ends a transaction or savepoint.
+func transactionControl(sql string) bool {
+	words := strings.Fields(sql)
+	if len(words) == 0 {
+		return false
+	}
+	switch strings.ToUpper(strings.TrimRight(words[0], ";")) {
+	case "BEGIN", "COMMIT", "END", "ROLLBACK", "SAVEPOINT", "RELEASE":
+		return true
+	}
+	return false
+}
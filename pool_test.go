@@ -0,0 +1,107 @@
+package sqlite3
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	opened := 0
+	p, e := NewPool(filepath.Join(t.TempDir(), "pool.db"), PoolConfig{
+		Readers:	2,
+		MaxIdle:	1,
+		Init:		func(db *Database) error {
+			opened++
+			return db.SetBusyTimeout(time.Second)
+		},
+	})
+	fatalOnError(t, e, "unable to create pool")
+	defer p.Close()
+
+	_, e = p.Execute("CREATE TABLE foo (number INTEGER, text VARCHAR(20))")
+	fatalOnError(t, e, "unable to create table")
+	_, e = p.Execute("INSERT INTO foo VALUES (1, 'pooled')")
+	fatalOnError(t, e, "unable to insert")
+	var text interface{}
+	c, e := p.Execute("SELECT text FROM foo", func(st *Statement, values ...interface{}) {
+		text = values[0]
+	})
+	fatalOnError(t, e, "unable to select")
+	if c != 1 || text != "pooled" {
+		t.Fatalf("unexpected result %v, %v", c, text)
+	}
+
+	for _, sql := range []string{"BEGIN", "commit", "SAVEPOINT a", "ROLLBACK TO a"} {
+		if _, e = p.Execute(sql); e != ErrPoolTransaction {
+			t.Fatalf("%v: expected %v, got %v", sql, ErrPoolTransaction, e)
+		}
+	}
+	_, e = p.Execute("INSERT INTO foo VALUES (4, 'routed')")
+	fatalOnError(t, e, "unable to insert again")
+	if write, ok := p.routes["INSERT INTO foo VALUES (4, 'routed')"]; !ok || !write {
+		t.Fatal("insert not routed to writer")
+	}
+	if write := p.routes["SELECT text FROM foo"]; write {
+		t.Fatal("select routed to writer")
+	}
+	_, e = p.Execute("DELETE FROM foo WHERE number = 4")
+	fatalOnError(t, e, "unable to delete")
+
+	writer, e := p.Acquire(context.Background(), true)
+	fatalOnError(t, e, "unable to acquire writer")
+	if v := writer.queryValue(t, "PRAGMA journal_mode"); v != "wal" {
+		t.Fatalf("expected WAL mode, got %v", v)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10 * time.Millisecond)
+	defer cancel()
+	if _, e = p.Acquire(ctx, true); !errors.Is(e, context.DeadlineExceeded) {
+		t.Fatalf("acquired second writer: %v", e)
+	}
+
+	r1, e := p.Acquire(context.Background(), false)
+	fatalOnError(t, e, "unable to acquire reader")
+	r2, e := p.Acquire(context.Background(), false)
+	fatalOnError(t, e, "unable to acquire reader")
+	writer.Begin()
+	writer.Execute("INSERT INTO foo VALUES (2, 'uncommitted')")
+	if v := r1.queryValue(t, "SELECT COUNT(*) FROM foo"); v != int64(1) {
+		t.Fatalf("reader saw uncommitted rows: %v", v)
+	}
+	if _, e = r2.Execute("INSERT INTO foo VALUES (3, 'reader')"); !errors.Is(e, READONLY) {
+		t.Fatalf("expected %v, got %v", READONLY, e)
+	}
+	p.Release(writer)
+	p.Release(r1)
+	p.Release(r2)
+	r1, _ = p.Acquire(context.Background(), false)
+	if v := r1.queryValue(t, "SELECT COUNT(*) FROM foo"); v != int64(1) {
+		t.Fatalf("open transaction not rolled back on release: %v", v)
+	}
+	p.Release(r1)
+
+	before := opened
+	r1, _ = p.Acquire(context.Background(), false)
+	r2, _ = p.Acquire(context.Background(), false)
+	p.Release(r1)
+	p.Release(r2)
+	if opened != before + 1 {
+		t.Fatalf("expected one idle reader to be kept, opened %v connections", opened - before)
+	}
+
+	p.MaxLifetime = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	before = opened
+	r1, _ = p.Acquire(context.Background(), false)
+	p.Release(r1)
+	if opened != before + 1 {
+		t.Fatal("expired connection was reused")
+	}
+
+	p.Close()
+	if _, e = p.Acquire(context.Background(), false); e != ErrPoolClosed {
+		t.Fatalf("expected %v, got %v", ErrPoolClosed, e)
+	}
+}
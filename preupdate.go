@@ -0,0 +1,90 @@
+//go:build sqlite_preupdate_hook
+
+package sqlite3
+
+// #cgo CFLAGS: -DSQLITE_ENABLE_PREUPDATE_HOOK
+// #include <sqlite3.h>
+// #include <stdint.h>
+// extern void gosqlite3_preupdate_hook(uintptr_t, int, char*, char*, sqlite3_int64, sqlite3_int64);
+// static void gosqlite3_preupdate(void* p, sqlite3* db, int op, const char* zDb, const char* zName, sqlite3_int64 iKey1, sqlite3_int64 iKey2) {
+//     gosqlite3_preupdate_hook((uintptr_t)p, op, (char*)zDb, (char*)zName, iKey1, iKey2);
+// }
+// static void gosqlite3_set_preupdate_hook(sqlite3* db, uintptr_t h) {
+//     sqlite3_preupdate_hook(db, h ? gosqlite3_preupdate : NULL, (void*)h);
+// }
+import "C"
+import (
+	"runtime/cgo"
+)
+
+type preUpdateHook struct {
+	db		*Database
+	f		func(*PreUpdate)
+}
+
+// OnPreUpdate registers `f` to be called before each row is inserted,
+// updated or deleted, giving access to the values of the row before and
+// after the change. Passing nil removes the hook. Panics in `f` are
+// recovered and ignored.
+//
+// The preupdate API is only present in SQLite3 libraries compiled with
+// SQLITE_ENABLE_PREUPDATE_HOOK, so it is only used when the package is
+// built with the sqlite_preupdate_hook tag. Otherwise OnPreUpdate returns
+// UNSUPPORTED.
+func (db *Database) OnPreUpdate(f func(*PreUpdate)) (e error) {
+	var v interface{}
+	if f != nil {
+		v = &preUpdateHook{db: db, f: f}
+	}
+	db.setHook("preupdate", v, func(h C.uintptr_t) {
+		C.gosqlite3_set_preupdate_hook(db.handle, h)
+	})
+	return
+}
+
+// Count returns the number of columns in the row being changed.
+func (p *PreUpdate) Count() int {
+	return int(C.sqlite3_preupdate_count(p.db.handle))
+}
+
+// Depth returns 0 for changes made directly by the statement being
+// executed, 1 for changes made by triggers it fires, and so on.
+func (p *PreUpdate) Depth() int {
+	return int(C.sqlite3_preupdate_depth(p.db.handle))
+}
+
+// Old returns the values of the row before an UPDATE or DELETE.
+func (p *PreUpdate) Old() (values []interface{}, e error) {
+	for i := 0; i < p.Count() && e == nil; i++ {
+		var v *C.sqlite3_value
+		if e = SQLiteError(C.sqlite3_preupdate_old(p.db.handle, C.int(i), &v)); e == nil {
+			values = append(values, Value{v}.Interface())
+		}
+	}
+	return
+}
+
+// New returns the values of the row after an INSERT or UPDATE.
+func (p *PreUpdate) New() (values []interface{}, e error) {
+	for i := 0; i < p.Count() && e == nil; i++ {
+		var v *C.sqlite3_value
+		if e = SQLiteError(C.sqlite3_preupdate_new(p.db.handle, C.int(i), &v)); e == nil {
+			values = append(values, Value{v}.Interface())
+		}
+	}
+	return
+}
+
+//export gosqlite3_preupdate_hook
+func gosqlite3_preupdate_hook(h C.uintptr_t, op C.int, db, table *C.char, oldRowid, newRowid C.sqlite3_int64) {
+	defer func() { recover() }()
+	hook := cgo.Handle(h).Value().(*preUpdateHook)
+	hook.f(&PreUpdate{
+		db:			hook.db,
+		Op:			Op(op),
+		DBName:		C.GoString(db),
+		Table:		C.GoString(table),
+		OldRowid:	int64(oldRowid),
+		NewRowid:	int64(newRowid),
+	})
+}
@@ -0,0 +1,30 @@
+//go:build !sqlite_preupdate_hook
+
+package sqlite3
+
+// OnPreUpdate is not available as the package was built without the
+// sqlite_preupdate_hook tag, and always returns UNSUPPORTED.
+func (db *Database) OnPreUpdate(f func(*PreUpdate)) error {
+	return UNSUPPORTED
+}
+
+// Count returns the number of columns in the row being changed.
+func (p *PreUpdate) Count() int {
+	return 0
+}
+
+// Depth returns 0 for changes made directly by the statement being
+// executed, 1 for changes made by triggers it fires, and so on.
+func (p *PreUpdate) Depth() int {
+	return 0
+}
+
+// Old returns the values of the row before an UPDATE or DELETE.
+func (p *PreUpdate) Old() ([]interface{}, error) {
+	return nil, UNSUPPORTED
+}
+
+// New returns the values of the row after an INSERT or UPDATE.
+func (p *PreUpdate) New() ([]interface{}, error) {
+	return nil, UNSUPPORTED
+}
@@ -10,16 +10,20 @@ package sqlite3
 // }
 import "C"
 import (
-	"bytes"
-	"encoding/gob"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
 	"unsafe"
 )
 
 type QueryParameter int
 func (p QueryParameter) bind_blob(s *Statement, v []byte) error {
-	cs := C.CString(string(v))
-	defer C.free(unsafe.Pointer(cs))
-	return SQLiteError(C.gosqlite3_bind_blob(s.cptr, C.int(p), unsafe.Pointer(cs), C.int(len(v))))
+	if len(v) == 0 {
+		return SQLiteError(C.sqlite3_bind_zeroblob(s.cptr, C.int(p), 0))
+	}
+	return SQLiteError(C.gosqlite3_bind_blob(s.cptr, C.int(p), unsafe.Pointer(&v[0]), C.int(len(v))))
 }
 
 // Bind replaces the literals placed in the SQL statement with the actual 
@@ -33,31 +37,118 @@ func (p QueryParameter) bind_blob(s *Statement, v []byte) error {
 //   - $VVV
 // In the templates above, NNN represents an integer literal, VVV represents
 // an alphanumeric identifier.
+//
+// Values implementing Valuer or driver.Valuer are bound as the value they
+// return. Integers, floats, strings and bools, including types derived from
+// them, are bound as INTEGER, FLOAT or TEXT, time.Time as TEXT in
+// TimeFormat, and []byte verbatim as a BLOB. nil values, nil pointers and
+// nil []byte are bound as NULL, and other pointers as the value they point
+// to. Values of any other type are encoded as a BLOB with the codec set for
+// a column of the same name as the parameter or for the database, and
+// otherwise must be wrapped with Encoded.
 func (p QueryParameter) Bind(s *Statement, value interface{}) (e error) {
+	v, e := sqliteValue(value)
+	if _, ok := e.(unsupportedType); ok {
+		if c := s.db.codecFor(strings.TrimLeft(s.ParameterName(int(p)), ":@$")); c != nil {
+			v, e = c.Encode(value)
+		}
+	}
+	if e != nil {
+		return
+	}
+	value = v
 	switch v := value.(type) {
 	case nil:
 		e = SQLiteError(C.sqlite3_bind_null(s.cptr, C.int(p)))
-	case int:
-		e = SQLiteError(C.sqlite3_bind_int(s.cptr, C.int(p), C.int(v)))
-	case string:
-		e = SQLiteError(C.gosqlite3_bind_text(s.cptr, C.int(p), C.CString(v), C.int(len(v))))
 	case int64:
 		e = SQLiteError(C.sqlite3_bind_int64(s.cptr, C.int(p), C.sqlite3_int64(v)))
-	case float32:
-		e = SQLiteError(C.sqlite3_bind_double(s.cptr, C.int(p), C.double(v)))
 	case float64:
 		e = SQLiteError(C.sqlite3_bind_double(s.cptr, C.int(p), C.double(v)))
-	default:
-		buffer := new(bytes.Buffer)
-		encoder := gob.NewEncoder(buffer)
-		if encoder.Encode(value) != nil {
-			e = ENCODER
-		} else {
-			rawbuffer := string(buffer.Bytes())
-			cs := C.CString(rawbuffer)
-			defer C.free(unsafe.Pointer(cs))
-			e = SQLiteError(C.gosqlite3_bind_blob(s.cptr, C.int(p), unsafe.Pointer(cs), C.int(len(rawbuffer))))
-		}
+	case string:
+		cs := C.CString(v)
+		defer C.free(unsafe.Pointer(cs))
+		e = SQLiteError(C.gosqlite3_bind_text(s.cptr, C.int(p), cs, C.int(len(v))))
+	case []byte:
+		e = p.bind_blob(s, v)
 	}
 	return
 }
+
+// sqliteValue converts `value` to nil, int64, float64, string or []byte,
+// the types with a native SQLite3 representation.
+func sqliteValue(value interface{}) (interface{}, error) {
+	return convertValue(value, true)
+}
+
+// convertValue is sqliteValue, calling Valuer and driver.Valuer methods
+// only if `custom` is set. As in database/sql, the value returned by such a
+// method is converted without calling them again, so that a method which
+// returns its own type can not recurse indefinitely.
+func convertValue(value interface{}, custom bool) (interface{}, error) {
+	if custom {
+		if v, ok, e := customValue(value); ok {
+			if e != nil {
+				return nil, e
+			}
+			x, e := convertValue(v, false)
+			if _, ok := e.(unsupportedType); ok {
+				e = fmt.Errorf("sqlite3: %T returned unsupported type %T", value, v)
+			}
+			return x, e
+		}
+	}
+	switch v := value.(type) {
+	case nil, int64, float64, string:
+		return v, nil
+	case []byte:
+		if v == nil {
+			return nil, nil
+		}
+		return v, nil
+	case int:
+		return int64(v), nil
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case time.Time:
+		return v.Format(TimeFormat), nil
+	case encodedValue:
+		return v.codec.Encode(v.value)
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("sqlite3: %v overflows INTEGER", v.Uint())
+		}
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return convertValue(v.Bool(), false)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return convertValue(v.Bytes(), false)
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return convertValue(v.Elem().Interface(), custom)
+	}
+	return nil, unsupportedType{value}
+}
+
+type unsupportedType struct {
+	value		interface{}
+}
+
+func (u unsupportedType) Error() string {
+	return fmt.Sprintf("sqlite3: unsupported type %T, set a Codec or use Encoded to store it", u.value)
+}
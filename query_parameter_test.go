@@ -1,6 +1,9 @@
 package sqlite3
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestQueryParameterBinding(t *testing.T) {
 	Session("test.db", func(db *Database) {
@@ -13,19 +16,68 @@ func TestQueryParameterBinding(t *testing.T) {
 		fatalOnError(t, e, "unable to prepare query: %v", SQL)
 		fatalOnError(t, QueryParameter(1).Bind(st, nil), "unable to bind NULL to column 1")
 
-		for _, v := range []interface{}{1.1, "hello", TwoItems{ "a", "b" }, []int{13, 27} } {
+		for _, v := range []interface{}{1.1, "hello", Gob(TwoItems{ "a", "b" }), Gob([]int{13, 27}), []byte("raw") } {
 			fatalOnError(t, QueryParameter(1).Bind(st, v), "erroneously bound %v to column 1", v)
 		}
 
 		fatalOnError(t, QueryParameter(1).Bind(st, 1), "unable to bind integer to column 1")
-		fatalOnError(t, QueryParameter(2).Bind(st, TwoItems{ "a", "b" }), "unable to bind blob to column 2")
+		fatalOnError(t, QueryParameter(2).Bind(st, Gob(TwoItems{ "a", "b" })), "unable to bind blob to column 2")
 
 		st, e = db.Prepare(SQL)
 		fatalOnError(t, e, "unable to prepare query: %v", st.SQLSource())
-		fatalOnError(t, QueryParameter(1).Bind(st, TwoItems{ "a", "b" }), "unable to bind blob to column 2")
+		fatalOnError(t, QueryParameter(1).Bind(st, Gob(TwoItems{ "a", "b" })), "unable to bind blob to column 2")
 
 		t.Logf("test case for issue #12")
-		db.runQuery(t, SQL, 1, TwoItems{ "a", "b" })
+		db.runQuery(t, SQL, 1, Gob(TwoItems{ "a", "b" }))
 		db.stepThroughRows(t, BAR)
 	})
-}
\ No newline at end of file
+}
+
+func TestRawBinding(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		BAR.Create(db)
+		db.runQuery(t, "INSERT INTO bar VALUES (1, ?)", []byte{0xde, 0xad, 0xbe, 0xef})
+		if v := db.queryValue(t, "SELECT hex(value) FROM bar WHERE number = 1"); v != "DEADBEEF" {
+			t.Fatalf("BLOB not stored verbatim: %v", v)
+		}
+		if v, ok := db.queryValue(t, "SELECT value FROM bar WHERE number = 1").([]byte); !ok || string(v) != "\xde\xad\xbe\xef" {
+			t.Fatalf("BLOB not returned verbatim: %v", v)
+		}
+		if v := db.queryValue(t, "SELECT typeof(?)", []byte{}); v != "blob" {
+			t.Fatalf("empty []byte bound as %v", v)
+		}
+
+		s := "pointer"
+		var missing *string
+		for _, c := range []struct {
+			value		interface{}
+			expected	interface{}
+		}{
+			{true, int64(1)},
+			{uint32(7), int64(7)},
+			{Level(3), int64(3)},
+			{float32(0.5), 0.5},
+			{&s, "pointer"},
+			{missing, nil},
+			{[]byte(nil), nil},
+			{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "2024-01-02 03:04:05+00:00"},
+		} {
+			if v := db.queryValue(t, "SELECT ?", c.value); v != c.expected {
+				t.Fatalf("%#v: expected %#v, got %#v", c.value, c.expected, v)
+			}
+		}
+
+		st, _ := db.Prepare("SELECT ?")
+		defer st.Finalize()
+		fatalOnSuccess(t, QueryParameter(1).Bind(st, TwoItems{ "a", "b" }), "bound struct without Gob")
+		fatalOnSuccess(t, QueryParameter(1).Bind(st, uint64(1 << 63)), "bound overflowing integer")
+
+		fatalOnError(t, QueryParameter(1).Bind(st, Gob(TwoItems{ "a", "b" })), "unable to bind gob value")
+		st.Step()
+		var items TwoItems
+		fatalOnError(t, GobDecode(st.Column(0).([]byte), &items), "unable to decode gob value")
+		if items.Text != "b" {
+			t.Fatalf("unexpected decoded value %v", items)
+		}
+	})
+}
@@ -2,11 +2,7 @@ package sqlite3
 
 // #include <sqlite3.h>
 import "C"
-import (
-	"bytes"
-	"encoding/gob"
-	"unsafe"
-)
+import "unsafe"
 
 const(
 	INTEGER = 1
@@ -47,7 +43,8 @@ func (c ResultColumn) ByteCount(s *Statement) int {
 	return int(C.sqlite3_column_bytes(s.cptr, C.int(c)))
 }
 
-// Value returns the value of the ResultColumn converted to a Go type.
+// Value returns the value of the ResultColumn converted to a Go type: int64,
+// float64, string, []byte or nil.
 func (c ResultColumn) Value(s *Statement) (value interface{}) {
 	switch c.Type(s) {
 	case INTEGER:
@@ -57,8 +54,7 @@ func (c ResultColumn) Value(s *Statement) (value interface{}) {
 	case TEXT:
 		value = c.make_buffer(s, C.sqlite3_column_text(s.cptr, C.int(c)))
 	case BLOB:
-		buffer := c.make_buffer(s, C.sqlite3_column_blob(s.cptr, C.int(c)))
-		value = gob.NewDecoder(bytes.NewBuffer([]byte(buffer)))
+		value = columnBytes(s, c)
 	case NULL:
 		value = nil
 	default:
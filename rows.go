@@ -0,0 +1,301 @@
+package sqlite3
+
+// #include <sqlite3.h>
+import "C"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Rows is a cursor over the result of Database.Query.
+//
+//	rows, e := db.Query("SELECT id, name FROM people WHERE age > ?", 18)
+//	...
+//	defer rows.Close()
+//	for rows.Next() {
+//		var id int64
+//		var name string
+//		if e := rows.Scan(&id, &name); e != nil {
+//			...
+//		}
+//	}
+//	if e := rows.Err(); e != nil {
+//		...
+//	}
+type Rows struct {
+	s			*Statement
+	e			error
+	closed		bool
+}
+
+// Query runs `sql` with `values` bound to its parameters and returns a
+// cursor over the resulting rows, using the statement cache if enabled.
+// The Rows must be closed once no longer needed.
+func (db *Database) Query(sql string, values ...interface{}) (r *Rows, e error) {
+	var s *Statement
+	if s, e = db.prepareCached(sql, values...); e == nil {
+		r = &Rows{s: s}
+	}
+	return
+}
+
+// Next advances to the next row, returning false at the end of the result
+// or on error. The Rows are closed automatically when Next returns false.
+func (r *Rows) Next() bool {
+	if r.closed {
+		return false
+	}
+	if e := r.s.Step(); e != ROW {
+		r.e = e
+		r.Close()
+		return false
+	}
+	return true
+}
+
+// Err returns the error, if any, which ended iteration.
+func (r *Rows) Err() error {
+	return r.e
+}
+
+// Close finalizes the statement underlying the Rows. It is safe to call
+// more than once.
+func (r *Rows) Close() (e error) {
+	if !r.closed {
+		r.closed = true
+		e = r.s.Finalize()
+	}
+	return
+}
+
+// Columns returns the number of columns in the result, or 0 once the Rows
+// are closed.
+func (r *Rows) Columns() int {
+	if r.closed {
+		return 0
+	}
+	return r.s.Columns()
+}
+
+// ColumnName returns the name of the column.
+func (r *Rows) ColumnName(column int) string {
+	if r.closed {
+		return ""
+	}
+	return r.s.ColumnName(column)
+}
+
+// Column returns the value of the column in the current row.
+func (r *Rows) Column(column int) interface{} {
+	if r.closed {
+		return nil
+	}
+	return r.s.Column(column)
+}
+
+// Values returns all values of the current row.
+func (r *Rows) Values() []interface{} {
+	if r.closed {
+		return nil
+	}
+	return r.s.Row()
+}
+
+// Scan copies the columns of the current row into `dest`, which must hold
+// one pointer per column. Supported destinations are *int, *int64,
+// *float64, *bool, *string, *[]byte, *time.Time and *interface{}, along
+// with pointers to other integer, float, string and bool types. NULL
+// values can be scanned into a pointer to one of these pointer types,
+// which is then set to nil, and into *[]byte and *interface{}. Destinations
+// implementing Scanner or sql.Scanner are passed the column value, and BLOB
+// and TEXT columns are decoded into other destinations with the codec set
+// for the column or database.
+func (r *Rows) Scan(dest ...interface{}) (e error) {
+	if r.closed {
+		return fmt.Errorf("sqlite3: Scan called on closed Rows")
+	}
+	return scanRow(r.s, dest)
+}
+
+// ScanStruct copies the columns of the current row into the struct pointed
+// to by `dest`, as for Statement.ScanStruct.
+func (r *Rows) ScanStruct(dest interface{}) (e error) {
+	if r.closed {
+		return fmt.Errorf("sqlite3: ScanStruct called on closed Rows")
+	}
+	return r.s.ScanStruct(dest)
+}
+
+func scanRow(s *Statement, dest []interface{}) (e error) {
+	if len(dest) != s.Columns() {
+		return fmt.Errorf("sqlite3: expected %v destinations, got %v", s.Columns(), len(dest))
+	}
+	for i, d := range dest {
+		if e = scanColumn(s, ResultColumn(i), d); e != nil {
+			return fmt.Errorf("sqlite3: column %v: %w", s.ColumnName(i), e)
+		}
+	}
+	return
+}
+
+func scanColumn(s *Statement, c ResultColumn, dest interface{}) (e error) {
+	t := c.Type(s)
+	if scan := scannerOf(dest); scan != nil {
+		return scan(c.Value(s))
+	}
+	switch d := dest.(type) {
+	case *interface{}:
+		*d = c.Value(s)
+		if td, ok := s.db.codecFor(c.Name(s)).(TypeDecoder); ok && t == BLOB {
+			var v interface{}
+			if v, e = td.DecodeValue(columnBytes(s, c)); e == nil {
+				*d = v
+			} else if errors.Is(e, ErrUntagged) {
+				e = nil
+			}
+		}
+		return
+	case *[]byte:
+		if t == NULL {
+			*d = nil
+		} else {
+			*d = columnBytes(s, c)
+		}
+		return
+	}
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("destination %T is not a non-nil pointer", dest)
+	}
+	if v = v.Elem(); v.Kind() == reflect.Ptr {
+		if t == NULL {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		if e = scanColumn(s, c, p.Interface()); e == nil {
+			v.Set(p)
+		}
+		return
+	}
+	if t == NULL {
+		return fmt.Errorf("can not scan NULL into %T", dest)
+	}
+	switch d := dest.(type) {
+	case *int:
+		var i int64
+		if i, e = columnInt64(s, c); e == nil {
+			*d = int(i)
+		}
+	case *int64:
+		*d, e = columnInt64(s, c)
+	case *float64:
+		*d, e = columnFloat64(s, c)
+	case *bool:
+		if t == TEXT {
+			*d, e = strconv.ParseBool(c.Value(s).(string))
+		} else {
+			var i int64
+			i, e = columnInt64(s, c)
+			*d = i != 0
+		}
+	case *string:
+		*d = c.make_buffer(s, C.sqlite3_column_text(s.cptr, C.int(c)))
+	case *time.Time:
+		switch t {
+		case TEXT:
+			*d, e = parseTime(c.Value(s).(string))
+		case INTEGER:
+			*d = time.Unix(int64(C.sqlite3_column_int64(s.cptr, C.int(c))), 0)
+		default:
+			e = fmt.Errorf("can not scan %v into %T", c.Value(s), dest)
+		}
+	default:
+		e = scanKind(s, c, v, dest)
+	}
+	return
+}
+
+// scanKind scans into destinations derived from the basic types by their
+// kind, falling back to the codec set for the column or database.
+func scanKind(s *Statement, c ResultColumn, v reflect.Value, dest interface{}) (e error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, e = columnInt64(s, c); e == nil {
+			if v.OverflowInt(i) {
+				return fmt.Errorf("%v overflows %T", i, dest)
+			}
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var i int64
+		if i, e = columnInt64(s, c); e == nil {
+			if i < 0 || v.OverflowUint(uint64(i)) {
+				return fmt.Errorf("%v overflows %T", i, dest)
+			}
+			v.SetUint(uint64(i))
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, e = columnFloat64(s, c); e == nil {
+			v.SetFloat(f)
+		}
+	case reflect.String:
+		v.SetString(c.make_buffer(s, C.sqlite3_column_text(s.cptr, C.int(c))))
+	case reflect.Bool:
+		var b bool
+		if e = scanColumn(s, c, &b); e == nil {
+			v.SetBool(b)
+		}
+	default:
+		t := c.Type(s)
+		if codec := s.db.codecFor(c.Name(s)); codec != nil && (t == BLOB || t == TEXT) {
+			e = codec.Decode(columnBytes(s, c), dest)
+		} else {
+			e = fmt.Errorf("unsupported destination %T", dest)
+		}
+	}
+	return
+}
+
+func columnBytes(s *Statement, c ResultColumn) []byte {
+	return C.GoBytes(C.sqlite3_column_blob(s.cptr, C.int(c)), C.int(c.ByteCount(s)))
+}
+
+func columnInt64(s *Statement, c ResultColumn) (i int64, e error) {
+	switch c.Type(s) {
+	case INTEGER:
+		i = int64(C.sqlite3_column_int64(s.cptr, C.int(c)))
+	case TEXT:
+		i, e = strconv.ParseInt(c.Value(s).(string), 10, 64)
+	default:
+		e = fmt.Errorf("can not convert %v to an integer", c.Value(s))
+	}
+	return
+}
+
+func columnFloat64(s *Statement, c ResultColumn) (f float64, e error) {
+	switch c.Type(s) {
+	case INTEGER, FLOAT:
+		f = float64(C.sqlite3_column_double(s.cptr, C.int(c)))
+	case TEXT:
+		f, e = strconv.ParseFloat(c.Value(s).(string), 64)
+	default:
+		e = fmt.Errorf("can not convert %v to a float", c.Value(s))
+	}
+	return
+}
+
+// parseTime parses `text` using the first matching layout in timeFormats.
+func parseTime(text string) (t time.Time, e error) {
+	for _, layout := range timeFormats {
+		if t, e = time.Parse(layout, text); e == nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,80 @@
+package sqlite3
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRowsScan(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		db.runQuery(t, "CREATE TABLE people (id INTEGER, name TEXT, score REAL, active INTEGER, avatar BLOB, born DATETIME, nickname TEXT)")
+		db.runQuery(t, "INSERT INTO people VALUES (1, 'Ada', 9.5, 1, x'0102', '1815-12-10 00:00:00', NULL)")
+		db.runQuery(t, "INSERT INTO people VALUES (2, 'Alan', 8, 0, NULL, '1912-06-23', 'Prof')")
+
+		rows, e := db.Query("SELECT id, name, score, active, avatar, born, nickname FROM people WHERE id >= ? ORDER BY id", 1)
+		fatalOnError(t, e, "unable to query")
+		defer rows.Close()
+		var ids []int
+		for rows.Next() {
+			var id int
+			var name string
+			var score float64
+			var active bool
+			var avatar []byte
+			var born time.Time
+			var nickname *string
+			fatalOnError(t, rows.Scan(&id, &name, &score, &active, &avatar, &born, &nickname), "unable to scan row")
+			ids = append(ids, id)
+			switch id {
+			case 1:
+				if name != "Ada" || score != 9.5 || !active || string(avatar) != "\x01\x02" || born.Year() != 1815 || nickname != nil {
+					t.Fatalf("unexpected row %v %v %v %v %v %v %v", id, name, score, active, avatar, born, nickname)
+				}
+			case 2:
+				if name != "Alan" || score != 8 || active || avatar != nil || born.Month() != time.June || nickname == nil || *nickname != "Prof" {
+					t.Fatalf("unexpected row %v %v %v %v %v %v %v", id, name, score, active, avatar, born, nickname)
+				}
+			}
+		}
+		fatalOnError(t, rows.Err(), "iteration failed")
+		if len(ids) != 2 {
+			t.Fatalf("expected 2 rows, got %v", ids)
+		}
+		if rows.Next() {
+			t.Fatal("Next succeeded after end of rows")
+		}
+
+		rows, _ = db.Query("SELECT nickname, id FROM people WHERE id = 1")
+		rows.Next()
+		var nickname string
+		var id int64
+		fatalOnSuccess(t, rows.Scan(&nickname, &id), "scanned NULL into string")
+		fatalOnSuccess(t, rows.Scan(&id), "scanned with too few destinations")
+		rows.Close()
+		fatalOnError(t, rows.Close(), "second Close failed")
+		if rows.Columns() != 0 || rows.Column(0) != nil || rows.Scan(&id) == nil {
+			t.Fatal("closed rows still usable")
+		}
+
+		db.SetStatementCacheSize(4)
+		rows, _ = db.Query("SELECT id FROM people")
+		rows.Close()
+		rows.Close()
+		first, _ := db.Query("SELECT id FROM people")
+		second, _ := db.Query("SELECT id FROM people")
+		if first.s == second.s {
+			t.Fatal("statement cached twice")
+		}
+		first.Close()
+		second.Close()
+
+		rows, _ = db.Query("SELECT * FROM nonexistent_function()")
+		if rows != nil {
+			t.Fatal("query on missing table function succeeded")
+		}
+		rows, _ = db.Query("SELECT abs(-9223372036854775807 - 1)")
+		if rows.Next() || rows.Err() == nil {
+			t.Fatal("expected integer overflow error")
+		}
+	})
+}
@@ -30,7 +30,7 @@ func TestBlob(t *testing.T) {
 		fatalOnError(t, encoder.Encode(TwoItems{ "holy", "moly guacomole" }), "Encoding failed: buffer = %v", buffer)
 		t.Logf("Encoded data: %v", buffer.Bytes())
 
-		db.runQuery(t, "INSERT INTO bar values (?, ?)", 1, TwoItems{ "holy moly", "guacomole" })
+		db.runQuery(t, "INSERT INTO bar values (?, ?)", 1, Gob(TwoItems{ "holy moly", "guacomole" }))
 		db.stepThroughRows(t, BAR)
 	})
 }
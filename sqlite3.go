@@ -40,13 +40,3 @@ func TransientSession(f func(db *Database)) {
 func LibVersion() string {
 	return C.GoString(C.sqlite3_libversion())
 }
-
-// Value represents any SQLite3 value.
-type Value struct {
-	cptr *C.sqlite3_value
-}
-
-// Blob represents the SQLite3 blob type.
-type Blob struct {
-	cptr *C.sqlite3_blob
-}
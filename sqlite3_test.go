@@ -2,6 +2,7 @@ package sqlite3
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -52,4 +53,13 @@ func TestOpen(t *testing.T) {
 	if _, e = db.Execute( "INSERT INTO foo (id,name) VALUES ('1', 'John');" ); e != nil {
 		t.Fatalf("Insert into foo failed with error: %v", e)
 	}
-}
\ No newline at end of file
+}
+func TestOpenFailure(t *testing.T) {
+	db := &Database{Filename: filepath.Join(t.TempDir(), "missing", "new.db")}
+	if e := db.Open(O_READWRITE, O_CREATE); e == nil {
+		t.Fatal("opened database in missing directory")
+	}
+	if db.handle != nil {
+		t.Fatal("failed Open left handle open")
+	}
+}
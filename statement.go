@@ -2,12 +2,15 @@ package sqlite3
 
 // #include <sqlite3.h>
 import "C"
+import "errors"
 
 // Statement represents a "SQL prepared Statement" also known as "compiled SQL statement".
 type Statement struct {
 	db			*Database
 	cptr		*C.sqlite3_stmt
 	timestamp	int64
+	cache		*statementCache
+	sql			string
 }
 
 // Parameters returns the number of SQL parameters.
@@ -77,15 +80,31 @@ func (s *Statement) SQLSource() (sql string) {
 	return
 }
 
+// ReadOnly reports whether the statement makes no direct changes to the
+// database.
+func (s *Statement) ReadOnly() bool {
+	return C.sqlite3_stmt_readonly(s.cptr) != 0
+}
+
 // Finalize is used to delete a prepared statement in the SQLite engine.
+// Statements obtained from the statement cache are reset and returned to
+// the cache instead. Finalizing a statement more than once has no effect.
 func (s *Statement) Finalize() (e error) {
-	return SQLiteError(C.sqlite3_finalize(s.cptr))
+	if s.cptr == nil {
+		return nil
+	}
+	if s.cache != nil && s.cache.put(s) {
+		return nil
+	}
+	e = s.db.lastError(C.sqlite3_finalize(s.cptr), "")
+	s.cptr = nil
+	return
 }
 
 // Step must be called one or more times to evaluate the statement after the 
 // prepared statement has been prepared.
 func (s *Statement) Step(f... func(*Statement, ...interface{})) (e error) {
-	switch e = SQLiteError(C.sqlite3_step(s.cptr)); e {
+	switch e = s.lastError(C.sqlite3_step(s.cptr)); e {
 	case ROW:
 		row := s.Row()
 		for _, fn := range f {
@@ -93,6 +112,11 @@ func (s *Statement) Step(f... func(*Statement, ...interface{})) (e error) {
 		}
 	case DONE:
 		e = s.Reset()
+	default:
+		if errors.Is(e, SCHEMA) {
+			s.cache = nil
+			s.db.cache.flush()
+		}
 	}
 	return
 }
@@ -103,7 +127,9 @@ func (s *Statement) All(f... func(*Statement, ...interface{})) (c int, e error)
 	for e = s.Step(f...); e == ROW; e = s.Step(f...) {
 		c++
 	}
-	e = s.Finalize()
+	if err := s.Finalize(); e == nil {
+		e = err
+	}
 	return
 }
 
@@ -113,7 +139,16 @@ func (s *Statement) All(f... func(*Statement, ...interface{})) (c int, e error)
 // Any SQL statement variables that had values bound to them retain 
 // their values. Use `ClearBindings` to reset the bindings.
 func (s *Statement) Reset() error {
-	return SQLiteError(C.sqlite3_reset(s.cptr))
+	return s.lastError(C.sqlite3_reset(s.cptr))
+}
+
+// lastError returns the error for `code` annotated with the statement's SQL.
+func (s *Statement) lastError(code C.int) error {
+	switch code {
+	case C.SQLITE_OK, C.SQLITE_ROW, C.SQLITE_DONE:
+		return SQLiteError(code)
+	}
+	return s.db.lastError(code, s.SQLSource())
 }
 
 // ClearBindings is used to reset all parameters to NULL.
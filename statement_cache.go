@@ -0,0 +1,144 @@
+package sqlite3
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CacheStats reports the use of a database's statement cache.
+type CacheStats struct {
+	Size		int
+	Cached		int
+	Hits		int64
+	Misses		int64
+	Evictions	int64
+}
+
+// statementCache holds idle prepared statements keyed by their SQL text,
+// most recently used first. Statements are removed from the cache while
+// in use, so each cached statement has at most one user.
+type statementCache struct {
+	sync.Mutex
+	size		int
+	lru			*list.List
+	idle		map[string]*list.Element
+	stats		CacheStats
+}
+
+// SetStatementCacheSize sets the number of idle prepared statements kept by
+// Execute and Query for reuse. A size of 0, the default, turns off the
+// cache and finalizes any statements it holds.
+func (db *Database) SetStatementCacheSize(n int) {
+	if db.cache == nil {
+		db.cache = &statementCache{lru: list.New(), idle: make(map[string]*list.Element)}
+	}
+	db.cache.Lock()
+	defer db.cache.Unlock()
+	db.cache.size = n
+	db.cache.trim()
+}
+
+// StatementCacheStats returns the current size and usage counts of the
+// statement cache.
+func (db *Database) StatementCacheStats() (s CacheStats) {
+	if c := db.cache; c != nil {
+		c.Lock()
+		defer c.Unlock()
+		s = c.stats
+		s.Size = c.size
+		s.Cached = c.lru.Len()
+	}
+	return
+}
+
+// prepareCached returns a prepared statement for `sql` with `values` bound
+// to its parameters, reusing an idle statement from the statement cache
+// when possible. Finalizing the statement returns it to the cache with its
+// bindings cleared.
+func (db *Database) prepareCached(sql string, values ...interface{}) (s *Statement, e error) {
+	if s = db.cache.get(sql); s == nil {
+		if s, e = db.Prepare(sql); e != nil {
+			return nil, e
+		}
+		if db.cache != nil {
+			s.cache = db.cache
+			s.sql = sql
+		}
+	}
+	if len(values) > 0 {
+		if e, _ = s.BindAll(values...); e != nil {
+			s.Finalize()
+			s = nil
+		}
+	}
+	return
+}
+
+func (c *statementCache) get(sql string) (s *Statement) {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.size < 1 {
+		return
+	}
+	if el, ok := c.idle[sql]; ok {
+		c.stats.Hits++
+		s = c.remove(el)
+	} else {
+		c.stats.Misses++
+	}
+	return
+}
+
+// put returns `s` to the cache, reporting false if it should be finalized
+// instead.
+func (c *statementCache) put(s *Statement) bool {
+	if s.Reset() != nil || s.ClearBindings() != nil {
+		return false
+	}
+	c.Lock()
+	defer c.Unlock()
+	if el, ok := c.idle[s.sql]; ok {
+		return el.Value == s
+	}
+	if c.size < 1 {
+		return false
+	}
+	c.idle[s.sql] = c.lru.PushFront(s)
+	c.trim()
+	return true
+}
+
+func (c *statementCache) remove(el *list.Element) (s *Statement) {
+	s = c.lru.Remove(el).(*Statement)
+	delete(c.idle, s.sql)
+	return
+}
+
+// trim evicts the least recently used statements beyond the cache size.
+func (c *statementCache) trim() {
+	for c.lru.Len() > c.size && c.lru.Len() > 0 {
+		c.stats.Evictions++
+		c.finalize(c.remove(c.lru.Back()))
+	}
+}
+
+// flush finalizes all idle statements, as needed when the schema changes or
+// the database is closed.
+func (c *statementCache) flush() {
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	for c.lru.Len() > 0 {
+		c.finalize(c.remove(c.lru.Front()))
+	}
+}
+
+func (c *statementCache) finalize(s *Statement) {
+	s.cache = nil
+	s.Finalize()
+}
@@ -0,0 +1,56 @@
+package sqlite3
+
+import "testing"
+
+func TestStatementCache(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		FOO.Create(db)
+		db.SetStatementCacheSize(2)
+		execute := func(sql string) {
+			_, e := db.Execute(sql)
+			fatalOnError(t, e, "%v", sql)
+		}
+		for i := 0; i < 3; i++ {
+			execute("INSERT INTO foo VALUES (1, 'cached')")
+		}
+		if s := db.StatementCacheStats(); s.Hits != 2 || s.Misses != 1 || s.Cached != 1 {
+			t.Fatalf("unexpected cache stats %+v", s)
+		}
+
+		rows, e := db.Query("SELECT text FROM foo WHERE number = ?", 1)
+		fatalOnError(t, e, "unable to query")
+		if !rows.Next() || rows.Column(0) != "cached" {
+			t.Fatalf("unexpected result %v, %v", rows.Err(), rows.Column(0))
+		}
+		fatalOnError(t, rows.Close(), "unable to return statement to cache")
+		again, e := db.Query("SELECT text FROM foo WHERE number = ?")
+		fatalOnError(t, e, "unable to query")
+		if again.s != rows.s {
+			t.Fatal("statement was not reused")
+		}
+		if again.Next() || again.Err() != nil {
+			t.Fatalf("bindings not cleared: %v", again.Err())
+		}
+
+		execute("SELECT COUNT(*) FROM foo")
+		if s := db.StatementCacheStats(); s.Evictions != 1 || s.Cached != 2 || s.Size != 2 {
+			t.Fatalf("unexpected cache stats %+v", s)
+		}
+
+		execute("DROP TABLE foo")
+		if _, e := db.Execute("SELECT COUNT(*) FROM foo"); e == nil {
+			t.Fatal("query on dropped table succeeded")
+		}
+		FOO.Create(db)
+		misses := db.StatementCacheStats().Misses
+		execute("SELECT COUNT(*) FROM foo")
+		if s := db.StatementCacheStats(); s.Misses != misses + 1 {
+			t.Fatalf("failed statement was returned to the cache: %+v", s)
+		}
+
+		db.SetStatementCacheSize(0)
+		if s := db.StatementCacheStats(); s.Cached != 0 {
+			t.Fatalf("cache not emptied: %+v", s)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package sqlite3
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoRows is returned by Get when the query produces no rows.
+var ErrNoRows = errors.New("sqlite3: no rows in result set")
+
+// structFields maps lower case column names to the index path of the
+// corresponding struct field.
+type structFields map[string][]int
+
+var structCache sync.Map
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldsOf returns the column mapping for struct type `t`, computing it on
+// first use.
+//
+// Exported fields are mapped to the column named by their `db` tag, or by
+// the field name if untagged, and skipped if tagged "-". Fields of untagged
+// embedded structs are mapped as if they belonged to the outer struct,
+// unless shadowed by a field of the same name at a shallower depth.
+// Unexported embedded struct pointers are skipped, as they can not be
+// allocated through reflection.
+func fieldsOf(t reflect.Type) structFields {
+	if f, ok := structCache.Load(t); ok {
+		return f.(structFields)
+	}
+	fields := make(structFields)
+	collectFields(fields, t, nil)
+	f, _ := structCache.LoadOrStore(t, fields)
+	return f.(structFields)
+}
+
+func collectFields(fields structFields, t reflect.Type, index []int) {
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("db")
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch {
+		case tag == "-":
+		case f.Anonymous && !f.IsExported() && f.Type.Kind() == reflect.Ptr:
+		case f.Anonymous && !tagged && ft.Kind() == reflect.Struct && ft != timeType:
+			embedded = append(embedded, f)
+		case !f.IsExported():
+		default:
+			if tag == "" {
+				tag = f.Name
+			}
+			if name := strings.ToLower(tag); fields[name] == nil {
+				fields[name] = append(append([]int{}, index...), i)
+			}
+		}
+	}
+	for _, f := range embedded {
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		nested := make(structFields)
+		collectFields(nested, ft, append(append([]int{}, index...), f.Index...))
+		for name, path := range nested {
+			if fields[name] == nil {
+				fields[name] = path
+			}
+		}
+	}
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but allocates nil
+// embedded struct pointers along the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func isStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+// ScanStruct copies the columns of the current row into the fields of the
+// struct pointed to by `dest`, matching column names to field names or `db`
+// tags without regard to case. Fields are converted as for Rows.Scan, with
+// pointer fields set to nil for NULL values. It is an error for a column to
+// have no matching field.
+func (s *Statement) ScanStruct(dest interface{}) (e error) {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || !isStruct(v.Elem().Type()) {
+		return fmt.Errorf("sqlite3: ScanStruct destination %T is not a pointer to a struct", dest)
+	}
+	v = v.Elem()
+	fields := fieldsOf(v.Type())
+	for i := 0; i < s.Columns(); i++ {
+		name := s.ColumnName(i)
+		index, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("sqlite3: no field for column %v in %v", name, v.Type())
+		}
+		if e = scanColumn(s, ResultColumn(i), fieldByIndex(v, index).Addr().Interface()); e != nil {
+			return fmt.Errorf("sqlite3: column %v: %w", name, e)
+		}
+	}
+	return
+}
+
+// ScanStruct copies the columns of the row into a struct, as for
+// Statement.ScanStruct.
+func (r *Row) ScanStruct(dest interface{}) error {
+	return r.s.ScanStruct(dest)
+}
+
+// scanInto scans the current row into a struct pointed to by `dest`, or
+// the single column into `dest` otherwise. A single column not matching any
+// field of the struct is scanned into the struct as a whole, so that it can
+// be decoded by a codec.
+func (s *Statement) scanInto(dest interface{}) error {
+	if t := reflect.TypeOf(dest); t.Kind() == reflect.Ptr && isStruct(t.Elem()) {
+		if _, ok := fieldsOf(t.Elem())[strings.ToLower(s.ColumnName(0))]; ok || s.Columns() != 1 {
+			return s.ScanStruct(dest)
+		}
+	}
+	return scanRow(s, []interface{}{dest})
+}
+
+// Select runs `sql` with `values` bound to its parameters and appends each
+// resulting row to the slice pointed to by `dest`. Rows are scanned into
+// struct or pointer to struct elements as by ScanStruct, and into other
+// element types as a single column.
+//
+//	var people []Person
+//	e := db.Select(&people, "SELECT * FROM people WHERE age > ?", 18)
+func (db *Database) Select(dest interface{}, sql string, values ...interface{}) (e error) {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("sqlite3: Select destination %T is not a pointer to a slice", dest)
+	}
+	v = v.Elem()
+	t := v.Type().Elem()
+	var s *Statement
+	if s, e = db.prepareCached(sql, values...); e != nil {
+		return
+	}
+	defer s.Finalize()
+	for row, err := range s.Rows() {
+		if e = err; e != nil {
+			break
+		}
+		p := t
+		if t.Kind() == reflect.Ptr && isStruct(t.Elem()) {
+			p = t.Elem()
+		}
+		x := reflect.New(p)
+		if e = row.s.scanInto(x.Interface()); e != nil {
+			break
+		}
+		if p != t {
+			v.Set(reflect.Append(v, x))
+		} else {
+			v.Set(reflect.Append(v, x.Elem()))
+		}
+	}
+	return
+}
+
+// Get runs `sql` with `values` bound to its parameters and scans the first
+// resulting row into `dest`, a pointer to a struct or to a single column
+// value. ErrNoRows is returned if there are no rows.
+func (db *Database) Get(dest interface{}, sql string, values ...interface{}) (e error) {
+	var s *Statement
+	if s, e = db.prepareCached(sql, values...); e != nil {
+		return
+	}
+	defer s.Finalize()
+	e = ErrNoRows
+	for row, err := range s.Rows() {
+		if e = err; e == nil {
+			e = row.s.scanInto(dest)
+		}
+		break
+	}
+	return
+}
@@ -0,0 +1,123 @@
+package sqlite3
+
+import (
+	"testing"
+	"time"
+)
+
+type Audit struct {
+	Created		time.Time	`db:"created_at"`
+}
+
+type Contact struct {
+	Email		*string
+}
+
+type Person struct {
+	ID			int64		`db:"id"`
+	Name		string
+	Secret		string		`db:"-"`
+	Audit
+	*Contact
+	internal	int
+}
+
+type alias struct {
+	Nick		string
+}
+
+type Member struct {
+	ID			int64
+	alias
+	*Contact	`db:"-"`
+	*Audit
+}
+
+type contact Contact
+
+type Guest struct {
+	ID			int64
+	*contact
+}
+
+func createPeople(t *testing.T, db *Database) {
+	db.runQuery(t, "CREATE TABLE people (id INTEGER PRIMARY KEY, name TEXT, email TEXT, created_at DATETIME)")
+	db.runQuery(t, "INSERT INTO people VALUES (1, 'Ada', 'ada@example.com', '2024-01-02 03:04:05')")
+	db.runQuery(t, "INSERT INTO people VALUES (2, 'Alan', NULL, '2024-02-03 04:05:06')")
+}
+
+func TestSelect(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		createPeople(t, db)
+		var people []Person
+		fatalOnError(t, db.Select(&people, "SELECT id, name, email, created_at FROM people ORDER BY id"), "unable to select")
+		if len(people) != 2 {
+			t.Fatalf("expected 2 people, got %v", people)
+		}
+		if p := people[0]; p.ID != 1 || p.Name != "Ada" || p.Contact == nil || *p.Email != "ada@example.com" || p.Created.Month() != time.January {
+			t.Fatalf("unexpected person %+v", p)
+		}
+		if p := people[1]; p.ID != 2 || p.Email != nil || p.Created.Day() != 3 {
+			t.Fatalf("unexpected person %+v", p)
+		}
+
+		var pointers []*Person
+		fatalOnError(t, db.Select(&pointers, "SELECT id, NAME FROM people WHERE id > ?", 1), "unable to select pointers")
+		if len(pointers) != 1 || pointers[0].Name != "Alan" {
+			t.Fatalf("unexpected people %v", pointers)
+		}
+
+		var names []string
+		fatalOnError(t, db.Select(&names, "SELECT name FROM people ORDER BY name DESC"), "unable to select names")
+		if len(names) != 2 || names[0] != "Alan" {
+			t.Fatalf("unexpected names %v", names)
+		}
+
+		var members []Member
+		fatalOnError(t, db.Select(&members, "SELECT id, name AS nick, created_at FROM people ORDER BY id"), "unable to select members")
+		if m := members[0]; m.ID != 1 || m.Nick != "Ada" || m.Contact != nil || m.Audit == nil || m.Created.Year() != 2024 {
+			t.Fatalf("unexpected member %+v", m)
+		}
+
+		var guests []Guest
+		fatalOnSuccess(t, db.Select(&guests, "SELECT 1 AS id, 'a@b' AS email"), "scanned into unexported embedded pointer")
+		fatalOnSuccess(t, db.Select(&members, "SELECT email FROM people"), "scanned into skipped embedded struct")
+		fatalOnSuccess(t, db.Select(&people, "SELECT name AS secret FROM people"), "scanned into skipped field")
+		fatalOnSuccess(t, db.Select(people, "SELECT id FROM people"), "selected into non-pointer")
+	})
+}
+
+func TestGet(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		createPeople(t, db)
+		var p Person
+		fatalOnError(t, db.Get(&p, "SELECT * FROM people WHERE id = ?", 2), "unable to get")
+		if p.ID != 2 || p.Name != "Alan" || p.Email != nil {
+			t.Fatalf("unexpected person %+v", p)
+		}
+		var count int
+		fatalOnError(t, db.Get(&count, "SELECT COUNT(*) FROM people"), "unable to get count")
+		if count != 2 {
+			t.Fatalf("expected 2, got %v", count)
+		}
+		if e := db.Get(&p, "SELECT * FROM people WHERE id = 3"); e != ErrNoRows {
+			t.Fatalf("expected %v, got %v", ErrNoRows, e)
+		}
+
+		rows, _ := db.Query("SELECT id, name FROM people ORDER BY id")
+		defer rows.Close()
+		rows.Next()
+		var q Person
+		fatalOnError(t, rows.ScanStruct(&q), "unable to scan struct")
+		if q.ID != 1 || q.Name != "Ada" {
+			t.Fatalf("unexpected person %+v", q)
+		}
+
+		for p, e := range QueryAll[Person](db, "SELECT id, name FROM people WHERE id = 2") {
+			fatalOnError(t, e, "iteration failed")
+			if p.Name != "Alan" {
+				t.Fatalf("unexpected person %+v", p)
+			}
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package sqlite3
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+// RowIterator produces the rows of a table-valued function. Next returns
+// the values of the next row, one for each visible column, or io.EOF once
+// all rows have been returned. If the RowIterator also implements io.Closer
+// it is closed when the scan ends.
+type RowIterator interface {
+	Next() ([]interface{}, error)
+}
+
+// tableFunction implements an eponymous-only virtual table which calls a
+// Go function to produce its rows. Columns declared HIDDEN hold the
+// arguments of the function.
+type tableFunction struct {
+	columns		[]string
+	hidden		[]int
+	visible		[]int
+	fn			func(args ...interface{}) (RowIterator, error)
+}
+
+// CreateTableFunction registers `fn` as the table-valued function `name`
+// which returns rows with the given `columns`.
+//
+// Columns declared with the HIDDEN keyword are the arguments of the
+// function, so that with columns {"value", "input HIDDEN", "sep HIDDEN"}
+//
+//   SELECT value FROM split('a,b,c', ',')
+//
+// calls `fn` with the arguments "a,b,c" and ",". Arguments which are not
+// supplied are omitted from the end of the argument list, or passed as nil
+// when followed by supplied ones.
+func (db *Database) CreateTableFunction(name string, columns []string, fn func(args ...interface{}) (RowIterator, error)) error {
+	f := &tableFunction{columns: columns, fn: fn}
+	for i, c := range columns {
+		if hiddenColumn(c) {
+			f.hidden = append(f.hidden, i)
+		} else {
+			f.visible = append(f.visible, i)
+		}
+	}
+	if len(f.hidden) > 30 {
+		return errors.New("sqlite3: too many table function arguments")
+	}
+	return db.createEponymousModule(name, f)
+}
+
+// hiddenColumn reports whether the column declaration `c` has HIDDEN as a
+// word of its declared type.
+func hiddenColumn(c string) bool {
+	words := strings.Fields(c)
+	for i := 1; i < len(words); i++ {
+		if strings.EqualFold(words[i], "HIDDEN") {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *tableFunction) Create(db *Database, args []string) (VTab, error) {
+	return nil, errors.New("sqlite3: table functions can not be created")
+}
+
+func (f *tableFunction) Connect(db *Database, args []string) (VTab, error) {
+	return f, db.DeclareVTab("CREATE TABLE x(" + strings.Join(f.columns, ", ") + ")")
+}
+
+// BestIndex passes equality constraints on the hidden columns to Filter,
+// recording which arguments were supplied as a bitmask in IdxNum. Plans in
+// which an argument is constrained but can not be used are made
+// prohibitively expensive.
+func (f *tableFunction) BestIndex(info *IndexInfo) error {
+	used := map[int]int{}
+	unusable := false
+	for i, c := range info.Constraints {
+		for a, column := range f.hidden {
+			if c.Column == column && c.Op == INDEX_EQ {
+				if c.Usable {
+					used[a] = i
+				} else {
+					unusable = true
+				}
+			}
+		}
+	}
+	argv := 1
+	for a := range f.hidden {
+		if i, ok := used[a]; ok {
+			info.IdxNum |= 1 << uint(a)
+			info.ConstraintUsage[i] = IndexConstraintUsage{ArgvIndex: argv, Omit: true}
+			argv++
+		}
+	}
+	info.EstimatedCost = 1000
+	if unusable {
+		info.EstimatedCost = math.MaxFloat64
+	}
+	return nil
+}
+
+func (f *tableFunction) Open() (VTabCursor, error) {
+	return &tableFunctionCursor{tableFunction: f}, nil
+}
+
+func (f *tableFunction) Disconnect() error {
+	return nil
+}
+
+func (f *tableFunction) Destroy() error {
+	return nil
+}
+
+type tableFunctionCursor struct {
+	*tableFunction
+	args		[]interface{}
+	rows		RowIterator
+	row			[]interface{}
+	rowid		int64
+	eof			bool
+}
+
+func (c *tableFunctionCursor) Filter(idxNum int, idxStr string, args ...interface{}) (e error) {
+	c.close()
+	c.args = make([]interface{}, len(c.hidden))
+	supplied := 0
+	for a := range c.hidden {
+		if idxNum & (1 << uint(a)) != 0 {
+			c.args[a] = args[0]
+			args = args[1:]
+			supplied = a + 1
+		}
+	}
+	c.rowid = 0
+	switch c.rows, e = c.fn(c.args[:supplied]...); {
+	case e != nil:
+	case c.rows == nil:
+		c.eof = true
+	default:
+		e = c.Next()
+	}
+	return
+}
+
+func (c *tableFunctionCursor) Next() (e error) {
+	switch c.row, e = c.rows.Next(); e {
+	case nil:
+		c.rowid++
+	case io.EOF:
+		c.eof, e = true, nil
+	}
+	return
+}
+
+func (c *tableFunctionCursor) EOF() bool {
+	return c.eof
+}
+
+func (c *tableFunctionCursor) Column(i int) (interface{}, error) {
+	for a, column := range c.hidden {
+		if column == i {
+			return c.args[a], nil
+		}
+	}
+	for v, column := range c.visible {
+		if column == i {
+			if v < len(c.row) {
+				return c.row[v], nil
+			}
+			break
+		}
+	}
+	return nil, nil
+}
+
+func (c *tableFunctionCursor) Rowid() (int64, error) {
+	return c.rowid, nil
+}
+
+func (c *tableFunctionCursor) Close() error {
+	return c.close()
+}
+
+func (c *tableFunctionCursor) close() (e error) {
+	if closer, ok := c.rows.(io.Closer); ok {
+		e = closer.Close()
+	}
+	c.rows, c.row, c.eof = nil, nil, false
+	return
+}
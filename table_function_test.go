@@ -0,0 +1,70 @@
+package sqlite3
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+type stringRows []string
+
+func (r *stringRows) Next() ([]interface{}, error) {
+	if len(*r) == 0 {
+		return nil, io.EOF
+	}
+	v := (*r)[0]
+	*r = (*r)[1:]
+	return []interface{}{v, int64(len(v))}, nil
+}
+
+func TestCreateTableFunction(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		fatalOnError(t, db.CreateTableFunction("split", []string{"value", "length", "input HIDDEN", "sep HIDDEN"}, func(args ...interface{}) (RowIterator, error) {
+			sep := ","
+			if len(args) > 1 {
+				sep = args[1].(string)
+			}
+			if len(args) == 0 || args[0] == nil {
+				return nil, nil
+			}
+			rows := stringRows(strings.Split(args[0].(string), sep))
+			return &rows, nil
+		}), "unable to register split")
+
+		values := []string{}
+		_, e := db.Execute("SELECT value, length, input FROM split('a;bb;ccc', ';')", func(s *Statement, v ...interface{}) {
+			if v[2] != "a;bb;ccc" || v[1] != int64(len(v[0].(string))) {
+				t.Fatalf("unexpected row %v", v)
+			}
+			values = append(values, v[0].(string))
+		})
+		fatalOnError(t, e, "unable to select from split")
+		if strings.Join(values, "|") != "a|bb|ccc" {
+			t.Fatalf("unexpected values %v", values)
+		}
+
+		if v := db.queryValue(t, "SELECT COUNT(*) FROM split('x,y')"); v != int64(2) {
+			t.Fatalf("split with default separator returned %v rows", v)
+		}
+		if v := db.queryValue(t, "SELECT COUNT(*) FROM split"); v != int64(0) {
+			t.Fatalf("split without arguments returned %v rows", v)
+		}
+
+		fatalOnError(t, db.CreateTableFunction("echo", []string{"hidden_name TEXT", "unhidden INTEGER", "input TEXT HIDDEN"}, func(args ...interface{}) (RowIterator, error) {
+			rows := stringRows{args[0].(string)}
+			return &rows, nil
+		}), "unable to register echo")
+		if v := db.queryValue(t, "SELECT hidden_name || unhidden FROM echo('abc')"); v != "abc3" {
+			t.Fatalf("echo returned %v", v)
+		}
+
+		FOO.Create(db)
+		db.runQuery(t, "INSERT INTO foo VALUES (1, 'p,q,r')")
+		if v := db.queryValue(t, "SELECT group_concat(s.value, '') FROM foo, split(foo.text) AS s"); v != "pqr" {
+			t.Fatalf("joined split returned %v", v)
+		}
+		if _, e = db.Execute("CREATE VIRTUAL TABLE nope USING split"); e == nil {
+			t.Fatal("created virtual table from table function")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdint.h>
+// extern void gosqlite3_trace_hook(uintptr_t, unsigned int, void*, void*);
+// static int gosqlite3_trace(unsigned int t, void* ctx, void* p, void* x) {
+//     gosqlite3_trace_hook((uintptr_t)ctx, t, p, x);
+//     return 0;
+// }
+// static int gosqlite3_set_trace(sqlite3* db, unsigned int mask, uintptr_t h) {
+//     return sqlite3_trace_v2(db, h ? mask : 0, h ? gosqlite3_trace : NULL, (void*)h);
+// }
+import "C"
+import (
+	"context"
+	"log/slog"
+	"runtime/cgo"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// TraceMask selects the events reported by Trace.
+type TraceMask uint
+
+const (
+	TRACE_STMT TraceMask =		0x01
+	TRACE_PROFILE TraceMask =	0x02
+	TRACE_ROW TraceMask =		0x04
+	TRACE_CLOSE TraceMask =		0x08
+)
+
+var traceText = map[TraceMask]string{
+	TRACE_STMT:		"STMT",
+	TRACE_PROFILE:	"PROFILE",
+	TRACE_ROW:		"ROW",
+	TRACE_CLOSE:	"CLOSE",
+}
+
+func (t TraceMask) String() string {
+	flags := []string{}
+	for i := TRACE_STMT; i <= TRACE_CLOSE; i <<= 1 {
+		if t & i != 0 {
+			flags = append(flags, traceText[i])
+		}
+	}
+	return strings.Join(flags, "|")
+}
+
+// TraceEvent describes a single event reported by Trace.
+//
+// For TRACE_STMT events SQL holds the text of the statement as it starts
+// running, or a comment naming the trigger for statements run by triggers,
+// and ExpandedSQL the statement text with its bound parameters substituted.
+// TRACE_PROFILE events carry the same ExpandedSQL and the wall time the
+// statement took to run in Duration. TRACE_ROW events report each row
+// returned, and TRACE_CLOSE events the closing of the database, for which
+// both SQL fields are empty.
+type TraceEvent struct {
+	Type			TraceMask
+	SQL				string
+	ExpandedSQL		string
+	Duration		time.Duration
+}
+
+// Trace registers `f` to receive the events selected by `mask`. Passing a
+// nil `f` or an empty mask stops tracing.
+//
+// `f` must not use the database connection. Panics in `f` are recovered and
+// ignored.
+func (db *Database) Trace(mask TraceMask, f func(TraceEvent)) (e error) {
+	var v interface{}
+	if f != nil && mask != 0 {
+		v = f
+	}
+	db.setHook("trace", v, func(h C.uintptr_t) {
+		e = SQLiteError(C.gosqlite3_set_trace(db.handle, C.uint(mask), h))
+	})
+	return
+}
+
+func expandedSQL(s *C.sqlite3_stmt) (sql string) {
+	if cs := C.sqlite3_expanded_sql(s); cs != nil {
+		sql = C.GoString(cs)
+		C.sqlite3_free(unsafe.Pointer(cs))
+	}
+	return
+}
+
+//export gosqlite3_trace_hook
+func gosqlite3_trace_hook(h C.uintptr_t, t C.uint, p, x unsafe.Pointer) {
+	defer func() { recover() }()
+	event := TraceEvent{Type: TraceMask(t)}
+	switch event.Type {
+	case TRACE_STMT:
+		event.SQL = C.GoString((*C.char)(x))
+		event.ExpandedSQL = expandedSQL((*C.sqlite3_stmt)(p))
+	case TRACE_PROFILE:
+		event.SQL = C.GoString(C.sqlite3_sql((*C.sqlite3_stmt)(p)))
+		event.ExpandedSQL = expandedSQL((*C.sqlite3_stmt)(p))
+		event.Duration = time.Duration(*(*C.sqlite3_int64)(x))
+	case TRACE_ROW:
+		event.SQL = C.GoString(C.sqlite3_sql((*C.sqlite3_stmt)(p)))
+	}
+	cgo.Handle(h).Value().(func(TraceEvent))(event)
+}
+
+// TraceLogger returns a function for use with Trace which logs each event
+// to `logger` at the given `level`.
+func TraceLogger(logger *slog.Logger, level slog.Level) func(TraceEvent) {
+	return func(e TraceEvent) {
+		attrs := []slog.Attr{slog.String("event", e.Type.String())}
+		if e.ExpandedSQL != "" {
+			attrs = append(attrs, slog.String("sql", e.ExpandedSQL))
+		} else if e.SQL != "" {
+			attrs = append(attrs, slog.String("sql", e.SQL))
+		}
+		if e.Type == TRACE_PROFILE {
+			attrs = append(attrs, slog.Duration("duration", e.Duration))
+		}
+		logger.LogAttrs(context.Background(), level, "sqlite3 trace", attrs...)
+	}
+}
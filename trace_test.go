@@ -0,0 +1,60 @@
+package sqlite3
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	db, e := Open(":memory:")
+	fatalOnError(t, e, "unable to open database")
+	events := []TraceEvent{}
+	fatalOnError(t, db.Trace(TRACE_STMT | TRACE_PROFILE | TRACE_ROW | TRACE_CLOSE, func(e TraceEvent) {
+		events = append(events, e)
+	}), "unable to set trace")
+
+	st, e := db.Prepare("SELECT ? UNION ALL SELECT 2", 1)
+	fatalOnError(t, e, "unable to prepare statement")
+	st.All()
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events, got %v", events)
+	}
+	if events[0].Type != TRACE_STMT || events[0].SQL != "SELECT ? UNION ALL SELECT 2" || events[0].ExpandedSQL != "SELECT 1 UNION ALL SELECT 2" {
+		t.Fatalf("unexpected statement event %+v", events[0])
+	}
+	if events[1].Type != TRACE_ROW || events[2].Type != TRACE_ROW {
+		t.Fatalf("expected row events, got %v and %v", events[1].Type, events[2].Type)
+	}
+	if events[3].Type != TRACE_PROFILE || events[3].ExpandedSQL != "SELECT 1 UNION ALL SELECT 2" || events[3].Duration < 0 {
+		t.Fatalf("unexpected profile event %+v", events[3])
+	}
+
+	fatalOnError(t, db.Trace(TRACE_STMT, func(TraceEvent) { panic("trace") }), "unable to set panicking trace")
+	_, e = db.Execute("SELECT 1")
+	fatalOnError(t, e, "panicking trace aborted statement")
+
+	events = events[:0]
+	fatalOnError(t, db.Trace(TRACE_CLOSE, func(e TraceEvent) {
+		events = append(events, e)
+	}), "unable to change trace mask")
+	db.Execute("SELECT 1")
+	db.Close()
+	if len(events) != 1 || events[0].Type != TRACE_CLOSE {
+		t.Fatalf("expected a single close event, got %v", events)
+	}
+}
+
+func TestTraceLogger(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		b := new(bytes.Buffer)
+		fatalOnError(t, db.Trace(TRACE_PROFILE, TraceLogger(slog.New(slog.NewTextHandler(b, nil)), slog.LevelInfo)), "unable to set trace")
+		db.Execute("SELECT 42")
+		fatalOnError(t, db.Trace(0, nil), "unable to remove trace")
+		db.Execute("SELECT 43")
+		if s := b.String(); !strings.Contains(s, `event=PROFILE sql="SELECT 42" duration=`) || strings.Contains(s, "SELECT 43") {
+			t.Fatalf("unexpected log output %q", s)
+		}
+	})
+}
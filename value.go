@@ -0,0 +1,63 @@
+package sqlite3
+
+// #include <sqlite3.h>
+import "C"
+import "unsafe"
+
+// Value represents any SQLite3 value, such as the arguments passed to a
+// user-defined function.
+type Value struct {
+	cptr *C.sqlite3_value
+}
+
+// Type returns the datatype code for the value.
+func (v Value) Type() int {
+	return int(C.sqlite3_value_type(v.cptr))
+}
+
+// ByteCount returns the number of bytes in a BLOB or string value.
+func (v Value) ByteCount() int {
+	return int(C.sqlite3_value_bytes(v.cptr))
+}
+
+// Int64 returns the value converted to an integer.
+func (v Value) Int64() int64 {
+	return int64(C.sqlite3_value_int64(v.cptr))
+}
+
+// Float64 returns the value converted to a floating point number.
+func (v Value) Float64() float64 {
+	return float64(C.sqlite3_value_double(v.cptr))
+}
+
+// Text returns the value converted to a string.
+func (v Value) Text() string {
+	p := C.sqlite3_value_text(v.cptr)
+	return C.GoStringN((*C.char)(unsafe.Pointer(p)), C.int(v.ByteCount()))
+}
+
+// Bytes returns a copy of the value as raw bytes.
+func (v Value) Bytes() []byte {
+	p := C.sqlite3_value_blob(v.cptr)
+	return C.GoBytes(p, C.int(v.ByteCount()))
+}
+
+// Interface returns the value converted to the Go type matching its
+// datatype: int64, float64, string, []byte or nil.
+func (v Value) Interface() (value interface{}) {
+	switch v.Type() {
+	case INTEGER:
+		value = v.Int64()
+	case FLOAT:
+		value = v.Float64()
+	case TEXT:
+		value = v.Text()
+	case BLOB:
+		value = v.Bytes()
+	case NULL:
+		value = nil
+	default:
+		panic("unknown value type")
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+)
+
+// Valuer is implemented by types which convert themselves to a value with a
+// native SQLite3 representation when bound as a parameter or returned from
+// a function. SQLiteValue may return any value QueryParameter.Bind accepts.
+type Valuer interface {
+	SQLiteValue() (interface{}, error)
+}
+
+// Scanner is implemented by types which can be scanned from a column or
+// received as a function argument. `src` is one of int64, float64, string,
+// []byte or nil.
+type Scanner interface {
+	SQLiteScan(src interface{}) error
+}
+
+var (
+	valuerType =		reflect.TypeOf((*Valuer)(nil)).Elem()
+	driverValuerType =	reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// customValue returns the result of calling SQLiteValue or Value on
+// `value`, and false if it implements neither Valuer nor driver.Valuer.
+// Methods with pointer receivers are called on a copy of `value`, and
+// methods with value receivers are not called for nil pointers, which are
+// left to be bound as NULL.
+func customValue(value interface{}) (v interface{}, ok bool, e error) {
+	x := reflect.ValueOf(value)
+	if !x.IsValid() {
+		return
+	}
+	t := x.Type()
+	if t.Kind() == reflect.Ptr && x.IsNil() {
+		return
+	}
+	if !t.Implements(valuerType) && !t.Implements(driverValuerType) {
+		if p := reflect.PointerTo(t); p.Implements(valuerType) || p.Implements(driverValuerType) {
+			c := reflect.New(t)
+			c.Elem().Set(x)
+			value = c.Interface()
+		}
+	}
+	switch u := value.(type) {
+	case Valuer:
+		v, e = u.SQLiteValue()
+		ok = true
+	case driver.Valuer:
+		v, e = u.Value()
+		ok = true
+	}
+	return
+}
+
+// scannerOf returns the SQLiteScan or Scan method of `dest` if it
+// implements Scanner or sql.Scanner, and nil otherwise.
+func scannerOf(dest interface{}) func(src interface{}) error {
+	switch d := dest.(type) {
+	case Scanner:
+		return d.SQLiteScan
+	case sql.Scanner:
+		return d.Scan
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type UUID [4]byte
+
+func (u UUID) SQLiteValue() (interface{}, error) {
+	return fmt.Sprintf("%x", u[:]), nil
+}
+
+func (u *UUID) SQLiteScan(src interface{}) (e error) {
+	s, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("can not scan %T into UUID", src)
+	}
+	var b []byte
+	if _, e = fmt.Sscanf(s, "%x", &b); e == nil {
+		copy(u[:], b)
+	}
+	return
+}
+
+type Cents int64
+
+func (c *Cents) SQLiteValue() (interface{}, error) {
+	return int64(*c), nil
+}
+
+type Color int
+
+func (c Color) SQLiteValue() (interface{}, error) {
+	return []string{"red", "green"}[c], nil
+}
+
+func (c *Color) SQLiteScan(src interface{}) error {
+	switch src {
+	case "red":
+		*c = 0
+	case "green":
+		*c = 1
+	default:
+		return fmt.Errorf("unknown color %v", src)
+	}
+	return nil
+}
+
+type Loop struct {
+	N			int
+}
+
+func (l Loop) SQLiteValue() (interface{}, error) {
+	return l, nil
+}
+
+type Echo int64
+
+func (e Echo) SQLiteValue() (interface{}, error) {
+	return e, nil
+}
+
+type Item struct {
+	ID			UUID
+	Price		Cents
+	Color		Color
+	Note		sql.NullString
+}
+
+func TestValuerAndScanner(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		db.runQuery(t, "CREATE TABLE items (id TEXT, price INTEGER, color TEXT, note TEXT)")
+		id := UUID{1, 2, 3, 4}
+		st, _ := db.Prepare("INSERT INTO items VALUES (:id, :price, :color, :note)")
+		fatalOnError(t, st.BindStruct(Item{ID: id, Price: 1999, Color: 1, Note: sql.NullString{String: "sale", Valid: true}}), "unable to bind")
+		fatalOnError(t, st.Step(), "unable to insert")
+		st.Finalize()
+		db.runQuery(t, "INSERT INTO items VALUES (?, ?, ?, ?)", UUID{5, 6, 7, 8}, Cents(5), Color(0), sql.NullString{})
+
+		if v := db.queryValue(t, "SELECT id || ':' || price || ':' || color FROM items WHERE note = 'sale'"); v != "01020304:1999:green" {
+			t.Fatalf("unexpected stored values %v", v)
+		}
+		var missing *UUID
+		if v := db.queryValue(t, "SELECT typeof(?)", missing); v != "null" {
+			t.Fatalf("nil pointer bound as %v", v)
+		}
+		st, _ = db.Prepare("SELECT ?")
+		defer st.Finalize()
+		if e := QueryParameter(1).Bind(st, Loop{1}); e == nil || !strings.Contains(e.Error(), "returned unsupported type") {
+			t.Fatalf("expected unsupported type error, got %v", e)
+		}
+		if v := db.queryValue(t, "SELECT ?", Echo(7)); v != int64(7) {
+			t.Fatalf("valuer returning its own type bound as %v", v)
+		}
+
+		var items []Item
+		fatalOnError(t, db.Select(&items, "SELECT id, price, color, note FROM items ORDER BY price DESC"), "unable to select")
+		if len(items) != 2 || items[0].ID != id || items[0].Color != 1 || items[0].Note.String != "sale" || items[1].Note.Valid {
+			t.Fatalf("unexpected items %+v", items)
+		}
+		var c Color
+		if e := db.Get(&c, "SELECT 'blue'"); e == nil || !strings.Contains(e.Error(), "unknown color blue") {
+			t.Fatalf("expected scan error, got %v", e)
+		}
+
+		fatalOnError(t, db.CreateFunction("complement", 1, F_DETERMINISTIC, func(c Color) Color {
+			return 1 - c
+		}), "unable to create function")
+		if v := db.queryValue(t, "SELECT complement('red')"); v != "green" {
+			t.Fatalf("expected green, got %v", v)
+		}
+	})
+}
+
+func TestDriverValuer(t *testing.T) {
+	db := openTestDriver(t)
+	defer db.Close()
+	_, e := db.Exec("CREATE TABLE items (id TEXT, color TEXT)")
+	fatalOnError(t, e, "unable to create table")
+	_, e = db.Exec("INSERT INTO items VALUES (?, ?)", UUID{9, 9, 9, 9}, Color(1))
+	fatalOnError(t, e, "unable to insert")
+	var id, color string
+	fatalOnError(t, db.QueryRow("SELECT id, color FROM items").Scan(&id, &color), "unable to query")
+	if id != "09090909" || color != "green" {
+		t.Fatalf("unexpected values %v, %v", id, color)
+	}
+}
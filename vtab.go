@@ -0,0 +1,526 @@
+package sqlite3
+
+// #include <sqlite3.h>
+// #include <stdint.h>
+// #include <stdlib.h>
+// #include <string.h>
+// typedef struct gosqlite3_vtab {
+//     sqlite3_vtab base;
+//     uintptr_t h;
+// } gosqlite3_vtab;
+// typedef struct gosqlite3_cursor {
+//     sqlite3_vtab_cursor base;
+//     uintptr_t h;
+// } gosqlite3_cursor;
+// extern int gosqlite3_vtab_connect(uintptr_t, int, char**, int, uintptr_t*, char**);
+// extern int gosqlite3_vtab_best_index(gosqlite3_vtab*, sqlite3_index_info*);
+// extern int gosqlite3_vtab_disconnect(gosqlite3_vtab*, int);
+// extern int gosqlite3_vtab_open(gosqlite3_vtab*, uintptr_t*);
+// extern int gosqlite3_vtab_close(gosqlite3_cursor*);
+// extern int gosqlite3_vtab_filter(gosqlite3_cursor*, int, char*, int, sqlite3_value**);
+// extern int gosqlite3_vtab_next(gosqlite3_cursor*);
+// extern int gosqlite3_vtab_eof(gosqlite3_cursor*);
+// extern int gosqlite3_vtab_column(gosqlite3_cursor*, sqlite3_context*, int);
+// extern int gosqlite3_vtab_rowid(gosqlite3_cursor*, sqlite3_int64*);
+// extern int gosqlite3_vtab_update(gosqlite3_vtab*, int, sqlite3_value**, sqlite3_int64*);
+// extern void gosqlite3_release(void*);
+// static int gosqlite3_xconnect_common(void* pAux, int argc, const char* const* argv, sqlite3_vtab** ppVTab, char** pzErr, int create) {
+//     gosqlite3_vtab* v = sqlite3_malloc(sizeof(gosqlite3_vtab));
+//     if (v == NULL) {
+//         return SQLITE_NOMEM;
+//     }
+//     memset(v, 0, sizeof(gosqlite3_vtab));
+//     int rc = gosqlite3_vtab_connect((uintptr_t)pAux, argc, (char**)argv, create, &v->h, pzErr);
+//     if (rc != SQLITE_OK) {
+//         sqlite3_free(v);
+//         return rc;
+//     }
+//     *ppVTab = &v->base;
+//     return SQLITE_OK;
+// }
+// static int gosqlite3_xcreate(sqlite3* db, void* pAux, int argc, const char* const* argv, sqlite3_vtab** ppVTab, char** pzErr) {
+//     return gosqlite3_xconnect_common(pAux, argc, argv, ppVTab, pzErr, 1);
+// }
+// static int gosqlite3_xconnect(sqlite3* db, void* pAux, int argc, const char* const* argv, sqlite3_vtab** ppVTab, char** pzErr) {
+//     return gosqlite3_xconnect_common(pAux, argc, argv, ppVTab, pzErr, 0);
+// }
+// static int gosqlite3_xbest_index(sqlite3_vtab* v, sqlite3_index_info* info) {
+//     return gosqlite3_vtab_best_index((gosqlite3_vtab*)v, info);
+// }
+// static int gosqlite3_xdisconnect(sqlite3_vtab* v) {
+//     int rc = gosqlite3_vtab_disconnect((gosqlite3_vtab*)v, 0);
+//     sqlite3_free(v);
+//     return rc;
+// }
+// static int gosqlite3_xdestroy(sqlite3_vtab* v) {
+//     int rc = gosqlite3_vtab_disconnect((gosqlite3_vtab*)v, 1);
+//     sqlite3_free(v);
+//     return rc;
+// }
+// static int gosqlite3_xopen(sqlite3_vtab* v, sqlite3_vtab_cursor** ppCursor) {
+//     gosqlite3_cursor* c = sqlite3_malloc(sizeof(gosqlite3_cursor));
+//     if (c == NULL) {
+//         return SQLITE_NOMEM;
+//     }
+//     memset(c, 0, sizeof(gosqlite3_cursor));
+//     int rc = gosqlite3_vtab_open((gosqlite3_vtab*)v, &c->h);
+//     if (rc != SQLITE_OK) {
+//         sqlite3_free(c);
+//         return rc;
+//     }
+//     *ppCursor = &c->base;
+//     return SQLITE_OK;
+// }
+// static int gosqlite3_xclose(sqlite3_vtab_cursor* c) {
+//     int rc = gosqlite3_vtab_close((gosqlite3_cursor*)c);
+//     sqlite3_free(c);
+//     return rc;
+// }
+// static int gosqlite3_xfilter(sqlite3_vtab_cursor* c, int idxNum, const char* idxStr, int argc, sqlite3_value** argv) {
+//     return gosqlite3_vtab_filter((gosqlite3_cursor*)c, idxNum, (char*)idxStr, argc, argv);
+// }
+// static int gosqlite3_xnext(sqlite3_vtab_cursor* c) {
+//     return gosqlite3_vtab_next((gosqlite3_cursor*)c);
+// }
+// static int gosqlite3_xeof(sqlite3_vtab_cursor* c) {
+//     return gosqlite3_vtab_eof((gosqlite3_cursor*)c);
+// }
+// static int gosqlite3_xcolumn(sqlite3_vtab_cursor* c, sqlite3_context* ctx, int i) {
+//     return gosqlite3_vtab_column((gosqlite3_cursor*)c, ctx, i);
+// }
+// static int gosqlite3_xrowid(sqlite3_vtab_cursor* c, sqlite3_int64* rowid) {
+//     return gosqlite3_vtab_rowid((gosqlite3_cursor*)c, rowid);
+// }
+// static int gosqlite3_xupdate(sqlite3_vtab* v, int argc, sqlite3_value** argv, sqlite3_int64* rowid) {
+//     return gosqlite3_vtab_update((gosqlite3_vtab*)v, argc, argv, rowid);
+// }
+// static sqlite3_module gosqlite3_module = {
+//     0,
+//     gosqlite3_xcreate,
+//     gosqlite3_xconnect,
+//     gosqlite3_xbest_index,
+//     gosqlite3_xdisconnect,
+//     gosqlite3_xdestroy,
+//     gosqlite3_xopen,
+//     gosqlite3_xclose,
+//     gosqlite3_xfilter,
+//     gosqlite3_xnext,
+//     gosqlite3_xeof,
+//     gosqlite3_xcolumn,
+//     gosqlite3_xrowid,
+//     gosqlite3_xupdate,
+// };
+// static sqlite3_module gosqlite3_eponymous_module = {
+//     0,
+//     NULL,
+//     gosqlite3_xconnect,
+//     gosqlite3_xbest_index,
+//     gosqlite3_xdisconnect,
+//     gosqlite3_xdestroy,
+//     gosqlite3_xopen,
+//     gosqlite3_xclose,
+//     gosqlite3_xfilter,
+//     gosqlite3_xnext,
+//     gosqlite3_xeof,
+//     gosqlite3_xcolumn,
+//     gosqlite3_xrowid,
+//     gosqlite3_xupdate,
+// };
+// static int gosqlite3_create_module(sqlite3* db, const char* name, uintptr_t h, int eponymous) {
+//     sqlite3_module* m = eponymous ? &gosqlite3_eponymous_module : &gosqlite3_module;
+//     return sqlite3_create_module_v2(db, name, m, (void*)h, gosqlite3_release);
+// }
+// static void gosqlite3_set_errmsg(char** p, const char* msg) {
+//     sqlite3_free(*p);
+//     *p = sqlite3_mprintf("%s", msg);
+// }
+// static char* gosqlite3_strdup(const char* s) {
+//     return sqlite3_mprintf("%s", s);
+// }
+import "C"
+import (
+	"errors"
+	"fmt"
+	"runtime/cgo"
+	"unsafe"
+)
+
+// IndexOp is the operator of a WHERE clause constraint passed to
+// VTab.BestIndex.
+type IndexOp int
+
+const (
+	INDEX_EQ IndexOp =			2
+	INDEX_GT IndexOp =			4
+	INDEX_LE IndexOp =			8
+	INDEX_LT IndexOp =			16
+	INDEX_GE IndexOp =			32
+	INDEX_MATCH IndexOp =		64
+	INDEX_LIKE IndexOp =		65
+	INDEX_GLOB IndexOp =		66
+	INDEX_REGEXP IndexOp =		67
+	INDEX_NE IndexOp =			68
+	INDEX_ISNOT IndexOp =		69
+	INDEX_ISNOTNULL IndexOp =	70
+	INDEX_ISNULL IndexOp =		71
+	INDEX_IS IndexOp =			72
+	INDEX_LIMIT IndexOp =		73
+	INDEX_OFFSET IndexOp =		74
+)
+
+// IndexConstraint describes a WHERE clause constraint on a column of a
+// virtual table. A Column of -1 refers to the rowid.
+type IndexConstraint struct {
+	Column		int
+	Op			IndexOp
+	Usable		bool
+}
+
+// IndexOrderBy describes an ORDER BY term on a column of a virtual table.
+type IndexOrderBy struct {
+	Column		int
+	Desc		bool
+}
+
+// IndexConstraintUsage tells SQLite how the virtual table uses the
+// matching IndexConstraint. A constraint with an ArgvIndex greater than zero
+// has its right-hand value passed to VTabCursor.Filter at position
+// ArgvIndex - 1, and Omit tells SQLite that it need not check the
+// constraint itself.
+type IndexConstraintUsage struct {
+	ArgvIndex	int
+	Omit		bool
+}
+
+// IndexInfo is used by VTab.BestIndex to choose a query plan. Constraints
+// and OrderBy are inputs; the remaining fields are outputs which are
+// passed back to SQLite and, for IdxNum and IdxStr, on to
+// VTabCursor.Filter.
+type IndexInfo struct {
+	Constraints			[]IndexConstraint
+	OrderBy				[]IndexOrderBy
+	ConstraintUsage		[]IndexConstraintUsage
+	IdxNum				int
+	IdxStr				string
+	OrderByConsumed		bool
+	EstimatedCost		float64
+	EstimatedRows		int64
+}
+
+// Module is a virtual table implementation registered with CreateModule.
+//
+// Create is called by CREATE VIRTUAL TABLE and Connect when an existing
+// virtual table is opened by a new connection. Both receive the module
+// name, database name, table name and module arguments in `args` and must
+// call Database.DeclareVTab to describe the columns of the table.
+type Module interface {
+	Create(db *Database, args []string) (VTab, error)
+	Connect(db *Database, args []string) (VTab, error)
+}
+
+// VTab is an instance of a virtual table.
+//
+// Disconnect is called when the connection no longer uses the table and
+// Destroy when it is dropped with DROP TABLE.
+type VTab interface {
+	BestIndex(info *IndexInfo) error
+	Open() (VTabCursor, error)
+	Disconnect() error
+	Destroy() error
+}
+
+// VTabUpdater may be implemented by a VTab to support INSERT, UPDATE and
+// DELETE. Insert is passed the requested rowid, or nil when none was given,
+// and returns the rowid of the new row.
+type VTabUpdater interface {
+	Insert(rowid interface{}, values []interface{}) (int64, error)
+	Update(old, rowid int64, values []interface{}) error
+	Delete(rowid int64) error
+}
+
+// VTabCursor iterates over the rows of a virtual table.
+//
+// Filter starts a new scan using the IdxNum and IdxStr chosen by BestIndex
+// and the constraint values requested through ConstraintUsage. A panic in
+// EOF ends the scan.
+type VTabCursor interface {
+	Filter(idxNum int, idxStr string, args ...interface{}) error
+	Next() error
+	EOF() bool
+	Column(i int) (interface{}, error)
+	Rowid() (int64, error)
+	Close() error
+}
+
+type module struct {
+	db		*Database
+	module	Module
+}
+
+// CreateModule registers the virtual table implementation `m` under
+// `name` so that it can be used with CREATE VIRTUAL TABLE ... USING name.
+func (db *Database) CreateModule(name string, m Module) (e error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return SQLiteError(C.gosqlite3_create_module(db.handle, cname, newHandle(&module{db: db, module: m}), 0))
+}
+
+// createEponymousModule registers `m` as an eponymous-only virtual table,
+// which exists under `name` in every schema and can not be created with
+// CREATE VIRTUAL TABLE. Only Module.Connect is ever called.
+func (db *Database) createEponymousModule(name string, m Module) (e error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return SQLiteError(C.gosqlite3_create_module(db.handle, cname, newHandle(&module{db: db, module: m}), 1))
+}
+
+// DeclareVTab declares the columns of a virtual table. It must be called
+// from Module.Create or Module.Connect with a CREATE TABLE statement.
+func (db *Database) DeclareVTab(sql string) error {
+	cs := C.CString(sql)
+	defer C.free(unsafe.Pointer(cs))
+	return SQLiteError(C.sqlite3_declare_vtab(db.handle, cs))
+}
+
+func errorCode(e error) C.int {
+	var err *Error
+	var n Errno
+	switch {
+	case errors.As(e, &err):
+		return C.int(err.ExtendedCode)
+	case errors.As(e, &n):
+		return C.int(n)
+	}
+	return C.SQLITE_ERROR
+}
+
+// vtabError reports `e` as the error message of the virtual table.
+func vtabError(v *C.sqlite3_vtab, e error) C.int {
+	cs := C.CString(e.Error())
+	defer C.free(unsafe.Pointer(cs))
+	C.gosqlite3_set_errmsg(&v.zErrMsg, cs)
+	return errorCode(e)
+}
+
+func recoverVTab(v *C.sqlite3_vtab, rc *C.int) {
+	if x := recover(); x != nil {
+		*rc = vtabError(v, fmt.Errorf("sqlite3: panic in virtual table: %v", x))
+	}
+}
+
+func vtabTable(v *C.gosqlite3_vtab) VTab {
+	return cgo.Handle(v.h).Value().(VTab)
+}
+
+func vtabCursor(c *C.gosqlite3_cursor) VTabCursor {
+	return cgo.Handle(c.h).Value().(VTabCursor)
+}
+
+func valueInterfaces(argv []*C.sqlite3_value) (values []interface{}) {
+	values = make([]interface{}, len(argv))
+	for i, v := range argv {
+		values[i] = (Value{v}).Interface()
+	}
+	return
+}
+
+//export gosqlite3_vtab_connect
+func gosqlite3_vtab_connect(h C.uintptr_t, argc C.int, argv **C.char, create C.int, table *C.uintptr_t, pzErr **C.char) (rc C.int) {
+	defer func() {
+		if x := recover(); x != nil {
+			cs := C.CString(fmt.Sprintf("sqlite3: panic in virtual table: %v", x))
+			defer C.free(unsafe.Pointer(cs))
+			*pzErr = C.gosqlite3_strdup(cs)
+			rc = C.SQLITE_ERROR
+		}
+	}()
+	m := cgo.Handle(h).Value().(*module)
+	args := make([]string, int(argc))
+	for i, s := range unsafe.Slice(argv, int(argc)) {
+		args[i] = C.GoString(s)
+	}
+	var t VTab
+	var e error
+	if create != 0 {
+		t, e = m.module.Create(m.db, args)
+	} else {
+		t, e = m.module.Connect(m.db, args)
+	}
+	if e != nil {
+		cs := C.CString(e.Error())
+		defer C.free(unsafe.Pointer(cs))
+		*pzErr = C.gosqlite3_strdup(cs)
+		return errorCode(e)
+	}
+	*table = newHandle(t)
+	return C.SQLITE_OK
+}
+
+//export gosqlite3_vtab_best_index
+func gosqlite3_vtab_best_index(v *C.gosqlite3_vtab, info *C.sqlite3_index_info) (rc C.int) {
+	defer recoverVTab(&v.base, &rc)
+	constraints := unsafe.Slice(info.aConstraint, int(info.nConstraint))
+	usage := unsafe.Slice(info.aConstraintUsage, int(info.nConstraint))
+	orderBy := unsafe.Slice(info.aOrderBy, int(info.nOrderBy))
+	ii := &IndexInfo{
+		Constraints:		make([]IndexConstraint, len(constraints)),
+		OrderBy:			make([]IndexOrderBy, len(orderBy)),
+		ConstraintUsage:	make([]IndexConstraintUsage, len(constraints)),
+		EstimatedCost:		float64(info.estimatedCost),
+		EstimatedRows:		int64(info.estimatedRows),
+	}
+	for i, c := range constraints {
+		ii.Constraints[i] = IndexConstraint{Column: int(c.iColumn), Op: IndexOp(c.op), Usable: c.usable != 0}
+	}
+	for i, o := range orderBy {
+		ii.OrderBy[i] = IndexOrderBy{Column: int(o.iColumn), Desc: o.desc != 0}
+	}
+	if e := vtabTable(v).BestIndex(ii); e != nil {
+		return vtabError(&v.base, e)
+	}
+	for i, u := range ii.ConstraintUsage {
+		if i < len(usage) {
+			usage[i].argvIndex = C.int(u.ArgvIndex)
+			usage[i].omit = 0
+			if u.Omit {
+				usage[i].omit = 1
+			}
+		}
+	}
+	info.idxNum = C.int(ii.IdxNum)
+	if ii.IdxStr != "" {
+		cs := C.CString(ii.IdxStr)
+		defer C.free(unsafe.Pointer(cs))
+		info.idxStr = C.gosqlite3_strdup(cs)
+		info.needToFreeIdxStr = 1
+	}
+	if ii.OrderByConsumed {
+		info.orderByConsumed = 1
+	}
+	info.estimatedCost = C.double(ii.EstimatedCost)
+	info.estimatedRows = C.sqlite3_int64(ii.EstimatedRows)
+	return C.SQLITE_OK
+}
+
+//export gosqlite3_vtab_disconnect
+func gosqlite3_vtab_disconnect(v *C.gosqlite3_vtab, destroy C.int) (rc C.int) {
+	defer recoverVTab(&v.base, &rc)
+	t := vtabTable(v)
+	defer cgo.Handle(v.h).Delete()
+	var e error
+	if destroy != 0 {
+		e = t.Destroy()
+	} else {
+		e = t.Disconnect()
+	}
+	if e != nil {
+		return errorCode(e)
+	}
+	return C.SQLITE_OK
+}
+
+//export gosqlite3_vtab_open
+func gosqlite3_vtab_open(v *C.gosqlite3_vtab, cursor *C.uintptr_t) (rc C.int) {
+	defer recoverVTab(&v.base, &rc)
+	c, e := vtabTable(v).Open()
+	if e != nil {
+		return vtabError(&v.base, e)
+	}
+	*cursor = newHandle(c)
+	return C.SQLITE_OK
+}
+
+//export gosqlite3_vtab_close
+func gosqlite3_vtab_close(c *C.gosqlite3_cursor) (rc C.int) {
+	defer recoverVTab(c.base.pVtab, &rc)
+	cursor := vtabCursor(c)
+	defer cgo.Handle(c.h).Delete()
+	if e := cursor.Close(); e != nil {
+		return vtabError(c.base.pVtab, e)
+	}
+	return C.SQLITE_OK
+}
+
+//export gosqlite3_vtab_filter
+func gosqlite3_vtab_filter(c *C.gosqlite3_cursor, idxNum C.int, idxStr *C.char, argc C.int, argv **C.sqlite3_value) (rc C.int) {
+	defer recoverVTab(c.base.pVtab, &rc)
+	var s string
+	if idxStr != nil {
+		s = C.GoString(idxStr)
+	}
+	if e := vtabCursor(c).Filter(int(idxNum), s, valueInterfaces(unsafe.Slice(argv, int(argc)))...); e != nil {
+		return vtabError(c.base.pVtab, e)
+	}
+	return C.SQLITE_OK
+}
+
+//export gosqlite3_vtab_next
+func gosqlite3_vtab_next(c *C.gosqlite3_cursor) (rc C.int) {
+	defer recoverVTab(c.base.pVtab, &rc)
+	if e := vtabCursor(c).Next(); e != nil {
+		return vtabError(c.base.pVtab, e)
+	}
+	return C.SQLITE_OK
+}
+
+//export gosqlite3_vtab_eof
+func gosqlite3_vtab_eof(c *C.gosqlite3_cursor) (r C.int) {
+	defer func() {
+		if recover() != nil {
+			r = 1
+		}
+	}()
+	if vtabCursor(c).EOF() {
+		return 1
+	}
+	return 0
+}
+
+//export gosqlite3_vtab_column
+func gosqlite3_vtab_column(c *C.gosqlite3_cursor, ctx *C.sqlite3_context, i C.int) (rc C.int) {
+	defer recoverVTab(c.base.pVtab, &rc)
+	v, e := vtabCursor(c).Column(int(i))
+	if e != nil {
+		return vtabError(c.base.pVtab, e)
+	}
+	setResult(ctx, v)
+	return C.SQLITE_OK
+}
+
+//export gosqlite3_vtab_rowid
+func gosqlite3_vtab_rowid(c *C.gosqlite3_cursor, rowid *C.sqlite3_int64) (rc C.int) {
+	defer recoverVTab(c.base.pVtab, &rc)
+	id, e := vtabCursor(c).Rowid()
+	if e != nil {
+		return vtabError(c.base.pVtab, e)
+	}
+	*rowid = C.sqlite3_int64(id)
+	return C.SQLITE_OK
+}
+
+//export gosqlite3_vtab_update
+func gosqlite3_vtab_update(v *C.gosqlite3_vtab, argc C.int, argv **C.sqlite3_value, rowid *C.sqlite3_int64) (rc C.int) {
+	defer recoverVTab(&v.base, &rc)
+	u, ok := vtabTable(v).(VTabUpdater)
+	if !ok {
+		return vtabError(&v.base, READONLY)
+	}
+	args := valueInterfaces(unsafe.Slice(argv, int(argc)))
+	var e error
+	switch {
+	case len(args) == 1:
+		e = u.Delete(args[0].(int64))
+	case args[0] == nil:
+		var id int64
+		if id, e = u.Insert(args[1], args[2:]); e == nil {
+			*rowid = C.sqlite3_int64(id)
+		}
+	default:
+		id, _ := args[1].(int64)
+		e = u.Update(args[0].(int64), id, args[2:])
+	}
+	if e != nil {
+		return vtabError(&v.base, e)
+	}
+	return C.SQLITE_OK
+}
@@ -0,0 +1,152 @@
+package sqlite3
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+// registry is a writable virtual table holding services by rowid.
+type registry struct {
+	services	map[int64]string
+	next		int64
+	broken		bool
+}
+
+func (r *registry) Create(db *Database, args []string) (VTab, error) {
+	return r, db.DeclareVTab("CREATE TABLE x(name TEXT)")
+}
+
+func (r *registry) Connect(db *Database, args []string) (VTab, error) {
+	return r.Create(db, args)
+}
+
+func (r *registry) BestIndex(info *IndexInfo) error {
+	info.IdxNum = 0
+	info.EstimatedCost = float64(len(r.services))
+	for i, c := range info.Constraints {
+		if c.Usable && c.Column == -1 && c.Op == INDEX_EQ {
+			info.IdxNum = 1
+			info.ConstraintUsage[i] = IndexConstraintUsage{ArgvIndex: 1, Omit: true}
+			info.EstimatedCost = 1
+			break
+		}
+	}
+	return nil
+}
+
+func (r *registry) Open() (VTabCursor, error) {
+	return &registryCursor{registry: r}, nil
+}
+
+func (r *registry) Disconnect() error {
+	return nil
+}
+
+func (r *registry) Destroy() error {
+	return nil
+}
+
+func (r *registry) Insert(rowid interface{}, values []interface{}) (int64, error) {
+	if rowid != nil {
+		return 0, errors.New("rowids are assigned by the registry")
+	}
+	r.next++
+	r.services[r.next] = values[0].(string)
+	return r.next, nil
+}
+
+func (r *registry) Update(old, rowid int64, values []interface{}) error {
+	delete(r.services, old)
+	r.services[rowid] = values[0].(string)
+	return nil
+}
+
+func (r *registry) Delete(rowid int64) error {
+	delete(r.services, rowid)
+	return nil
+}
+
+type registryCursor struct {
+	*registry
+	rowids	[]int64
+}
+
+func (c *registryCursor) Filter(idxNum int, idxStr string, args ...interface{}) error {
+	c.rowids = nil
+	if idxNum == 1 {
+		if _, ok := c.services[args[0].(int64)]; ok {
+			c.rowids = append(c.rowids, args[0].(int64))
+		}
+		return nil
+	}
+	for id := range c.services {
+		c.rowids = append(c.rowids, id)
+	}
+	sort.Slice(c.rowids, func(i, j int) bool { return c.rowids[i] < c.rowids[j] })
+	return nil
+}
+
+func (c *registryCursor) Next() error {
+	c.rowids = c.rowids[1:]
+	return nil
+}
+
+func (c *registryCursor) EOF() bool {
+	if c.broken {
+		panic("broken cursor")
+	}
+	return len(c.rowids) == 0
+}
+
+func (c *registryCursor) Column(i int) (interface{}, error) {
+	return c.services[c.rowids[0]], nil
+}
+
+func (c *registryCursor) Rowid() (int64, error) {
+	return c.rowids[0], nil
+}
+
+func (c *registryCursor) Close() error {
+	return nil
+}
+
+func TestVirtualTable(t *testing.T) {
+	Session(":memory:", func(db *Database) {
+		r := &registry{services: map[int64]string{}}
+		fatalOnError(t, db.CreateModule("registry", r), "unable to create module")
+		_, e := db.Execute("CREATE VIRTUAL TABLE services USING registry")
+		fatalOnError(t, e, "unable to create virtual table")
+
+		for _, name := range []string{"auth", "billing", "search"} {
+			db.runQuery(t, "INSERT INTO services (name) VALUES (?)", name)
+		}
+		if len(r.services) != 3 {
+			t.Fatalf("expected 3 services, found %v", r.services)
+		}
+		db.runQuery(t, "UPDATE services SET name = 'payments' WHERE rowid = 2")
+		db.runQuery(t, "DELETE FROM services WHERE name = 'search'")
+
+		names := []string{}
+		_, e = db.Execute("SELECT rowid, name FROM services", func(s *Statement, values ...interface{}) {
+			names = append(names, values[1].(string))
+		})
+		fatalOnError(t, e, "unable to scan virtual table")
+		if len(names) != 2 || names[0] != "auth" || names[1] != "payments" {
+			t.Fatalf("unexpected services %v", names)
+		}
+		if v := db.queryValue(t, "SELECT name FROM services WHERE rowid = ?", 1); v != "auth" {
+			t.Fatalf("rowid lookup returned %v", v)
+		}
+		if _, e = db.Execute("INSERT INTO services (rowid, name) VALUES (7, 'mail')"); e == nil {
+			t.Fatal("insert with explicit rowid should have failed")
+		}
+		r.broken = true
+		if v := db.queryValue(t, "SELECT COUNT(*) FROM services"); v != int64(0) {
+			t.Fatalf("panicking cursor returned %v rows", v)
+		}
+		r.broken = false
+		_, e = db.Execute("DROP TABLE services")
+		fatalOnError(t, e, "unable to drop virtual table")
+	})
+}